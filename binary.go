@@ -0,0 +1,398 @@
+package collection
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+)
+
+// BinaryVersion is the version of the binary format written by MarshalBinary
+// and BinaryEncoder. Blobs carrying a different version are rejected.
+const BinaryVersion = 1
+
+// MaxBinaryItemSize is the largest encoded item BinaryDecoder accepts. Longer
+// length prefixes are treated as corruption rather than allocated.
+const MaxBinaryItemSize = 1 << 30
+
+var binaryMagic = [4]byte{'G', 'C', 'O', 'L'}
+
+const (
+	binaryKindGob    byte = 'g'
+	binaryKindStream byte = 's'
+)
+
+var (
+	// ErrInvalidHeader is returned when decoding data which does not start with
+	// a collection header.
+	ErrInvalidHeader = errors.New("collection: invalid binary header")
+	// ErrUnsupportedVersion is returned when decoding data written with an
+	// unknown version of the binary format.
+	ErrUnsupportedVersion = errors.New("collection: unsupported binary version")
+	// ErrChecksumMismatch is returned when decoded data does not match its
+	// checksum, usually because it was truncated or corrupted.
+	ErrChecksumMismatch = errors.New("collection: binary checksum mismatch")
+)
+
+// gobItems wraps a collection's items so that empty collections can be gob
+// encoded.
+type gobItems[T any] struct {
+	Items []T
+}
+
+// GobEncode implements the gob.GobEncoder interface so the current collection
+// can be embedded in gob encoded values. Interface item types must be
+// registered with gob.Register.
+func (c *Collection[T]) GobEncode() ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(gobItems[T]{c.snapshot()}); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// GobDecode implements the gob.GobDecoder interface, replacing the current
+// collection's items with those found in the specified data. The items must
+// satisfy the collection's validation rules.
+func (c *Collection[T]) GobDecode(data []byte) error {
+	var out gobItems[T]
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&out); err != nil {
+		return err
+	}
+
+	defer c.lock()()
+
+	return c.replace(out.Items)
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface. Items are
+// gob encoded behind a versioned header and followed by a checksum so stored
+// blobs can be validated by UnmarshalBinary.
+func (c *Collection[T]) MarshalBinary() ([]byte, error) {
+	payload, err := c.GobEncode()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, len(binaryMagic)+2+len(payload)+crc32.Size)
+	out = append(out, binaryMagic[:]...)
+	out = append(out, BinaryVersion, binaryKindGob)
+	out = append(out, payload...)
+	out = binary.BigEndian.AppendUint32(out, crc32.ChecksumIEEE(payload))
+
+	return out, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface,
+// replacing the current collection's items with those found in data produced
+// by MarshalBinary.
+func (c *Collection[T]) UnmarshalBinary(data []byte) error {
+	if err := checkBinaryHeader(data, binaryKindGob); err != nil {
+		return err
+	}
+
+	data = data[len(binaryMagic)+2:]
+	if len(data) < crc32.Size {
+		return ErrChecksumMismatch
+	}
+
+	payload, sum := data[:len(data)-crc32.Size], data[len(data)-crc32.Size:]
+	if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(sum) {
+		return ErrChecksumMismatch
+	}
+
+	return c.GobDecode(payload)
+}
+
+func checkBinaryHeader(header []byte, kind byte) error {
+	if len(header) < len(binaryMagic)+2 || !bytes.Equal(header[:len(binaryMagic)], binaryMagic[:]) {
+		return ErrInvalidHeader
+	}
+
+	if version := header[len(binaryMagic)]; version != BinaryVersion {
+		return fmt.Errorf("%w: %d", ErrUnsupportedVersion, version)
+	}
+
+	if header[len(binaryMagic)+1] != kind {
+		return ErrInvalidHeader
+	}
+
+	return nil
+}
+
+// ElementCodec encodes and decodes individual items of type T for use with
+// BinaryEncoder and BinaryDecoder.
+type ElementCodec[T any] interface {
+	// AppendElement appends the encoded form of item to dst and returns the
+	// extended slice.
+	AppendElement(dst []byte, item T) ([]byte, error)
+	// DecodeElement decodes a single item from data.
+	DecodeElement(data []byte) (T, error)
+}
+
+// ElementCodecFuncs adapts a pair of functions to the ElementCodec interface.
+type ElementCodecFuncs[T any] struct {
+	Append func(dst []byte, item T) ([]byte, error)
+	Decode func(data []byte) (T, error)
+}
+
+// AppendElement calls f.Append(dst, item).
+func (f ElementCodecFuncs[T]) AppendElement(dst []byte, item T) ([]byte, error) {
+	return f.Append(dst, item)
+}
+
+// DecodeElement calls f.Decode(data).
+func (f ElementCodecFuncs[T]) DecodeElement(data []byte) (T, error) {
+	return f.Decode(data)
+}
+
+// JSONElementCodec encodes each item as JSON.
+type JSONElementCodec[T any] struct{}
+
+// AppendElement appends the JSON encoding of item to dst.
+func (JSONElementCodec[T]) AppendElement(dst []byte, item T) ([]byte, error) {
+	data, err := json.Marshal(item)
+	return append(dst, data...), err
+}
+
+// DecodeElement decodes a single JSON encoded item from data.
+func (JSONElementCodec[T]) DecodeElement(data []byte) (out T, err error) {
+	err = json.Unmarshal(data, &out)
+	return out, err
+}
+
+// GobElementCodec encodes each item as a self-contained gob. This is
+// convenient but repeats type information for every item; prefer a dedicated
+// codec for large streams.
+type GobElementCodec[T any] struct{}
+
+// AppendElement appends the gob encoding of item to dst.
+func (GobElementCodec[T]) AppendElement(dst []byte, item T) ([]byte, error) {
+	buffer := bytes.NewBuffer(dst)
+	err := gob.NewEncoder(buffer).Encode(&item)
+	return buffer.Bytes(), err
+}
+
+// DecodeElement decodes a single gob encoded item from data.
+func (GobElementCodec[T]) DecodeElement(data []byte) (out T, err error) {
+	err = gob.NewDecoder(bytes.NewReader(data)).Decode(&out)
+	return out, err
+}
+
+// BinaryEncoder writes a stream of length-prefixed items, each encoded with an
+// ElementCodec, behind a versioned header. Close must be called to write the
+// trailing checksum which allows BinaryDecoder to detect truncated streams.
+type BinaryEncoder[T any] struct {
+	w       *bufio.Writer
+	codec   ElementCodec[T]
+	sum     hash.Hash32
+	scratch []byte
+	started bool
+	closed  bool
+}
+
+// NewBinaryEncoder returns a new encoder writing items to the specified writer
+// using the specified codec.
+func NewBinaryEncoder[T any](w io.Writer, codec ElementCodec[T]) *BinaryEncoder[T] {
+	return &BinaryEncoder[T]{
+		w:     bufio.NewWriter(w),
+		codec: codec,
+		sum:   crc32.NewIEEE(),
+	}
+}
+
+func (e *BinaryEncoder[T]) writeHeader() error {
+	if e.started {
+		return nil
+	}
+	e.started = true
+
+	_, err := e.w.Write(append(binaryMagic[:], BinaryVersion, binaryKindStream))
+	return err
+}
+
+func (e *BinaryEncoder[T]) writeRecord(data []byte) error {
+	var prefix [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(prefix[:], uint64(len(data))+1)
+
+	e.sum.Write(prefix[:n])
+	e.sum.Write(data)
+
+	if _, err := e.w.Write(prefix[:n]); err != nil {
+		return err
+	}
+
+	_, err := e.w.Write(data)
+	return err
+}
+
+// Encode writes the specified item to the stream.
+func (e *BinaryEncoder[T]) Encode(item T) error {
+	if e.closed {
+		return errors.New("collection: encode on closed binary encoder")
+	}
+
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+
+	data, err := e.codec.AppendElement(e.scratch[:0], item)
+	if err != nil {
+		return err
+	}
+	e.scratch = data
+
+	return e.writeRecord(data)
+}
+
+// Close terminates the stream, writes its checksum and flushes any buffered
+// data to the underlying writer. It does not close the underlying writer.
+func (e *BinaryEncoder[T]) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+
+	if err := e.w.WriteByte(0); err != nil {
+		return err
+	}
+
+	if _, err := e.w.Write(binary.BigEndian.AppendUint32(nil, e.sum.Sum32())); err != nil {
+		return err
+	}
+
+	return e.w.Flush()
+}
+
+// BinaryDecoder reads a stream of items written by a BinaryEncoder.
+type BinaryDecoder[T any] struct {
+	r       *bufio.Reader
+	codec   ElementCodec[T]
+	sum     hash.Hash32
+	scratch bytes.Buffer
+	started bool
+	done    bool
+}
+
+// NewBinaryDecoder returns a new decoder reading items from the specified
+// reader using the specified codec.
+func NewBinaryDecoder[T any](r io.Reader, codec ElementCodec[T]) *BinaryDecoder[T] {
+	return &BinaryDecoder[T]{
+		r:     bufio.NewReader(r),
+		codec: codec,
+		sum:   crc32.NewIEEE(),
+	}
+}
+
+// Decode reads the next item from the stream. It returns io.EOF once the end
+// of a complete stream has been reached and ErrChecksumMismatch, or
+// io.ErrUnexpectedEOF, if the stream is corrupt or truncated.
+func (d *BinaryDecoder[T]) Decode() (out T, err error) {
+	if d.done {
+		return out, io.EOF
+	}
+
+	if !d.started {
+		header := make([]byte, len(binaryMagic)+2)
+		if _, err := io.ReadFull(d.r, header); err != nil {
+			return out, ErrInvalidHeader
+		}
+
+		if err := checkBinaryHeader(header, binaryKindStream); err != nil {
+			return out, err
+		}
+		d.started = true
+	}
+
+	length, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		return out, unexpectedEOF(err)
+	}
+
+	if length == 0 {
+		return out, d.finish()
+	}
+
+	if length-1 > MaxBinaryItemSize {
+		d.done = true
+		return out, ErrChecksumMismatch
+	}
+
+	var prefix [binary.MaxVarintLen64]byte
+	d.sum.Write(prefix[:binary.PutUvarint(prefix[:], length)])
+
+	// The buffer grows as data arrives, so a corrupt length cannot allocate
+	// more than the stream actually holds.
+	d.scratch.Reset()
+	if _, err := io.CopyN(&d.scratch, d.r, int64(length-1)); err != nil {
+		return out, unexpectedEOF(err)
+	}
+	d.sum.Write(d.scratch.Bytes())
+
+	return d.codec.DecodeElement(d.scratch.Bytes())
+}
+
+func (d *BinaryDecoder[T]) finish() error {
+	d.done = true
+
+	var sum [crc32.Size]byte
+	if _, err := io.ReadFull(d.r, sum[:]); err != nil {
+		return unexpectedEOF(err)
+	}
+
+	if binary.BigEndian.Uint32(sum[:]) != d.sum.Sum32() {
+		return ErrChecksumMismatch
+	}
+
+	return io.EOF
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
+
+// WriteBinary writes the current collection's items to the specified writer as
+// a length-prefixed binary stream using the specified codec.
+func (c *Collection[T]) WriteBinary(w io.Writer, codec ElementCodec[T]) error {
+	encoder := NewBinaryEncoder(w, codec)
+	for _, item := range c.snapshot() {
+		if err := encoder.Encode(item); err != nil {
+			return err
+		}
+	}
+
+	return encoder.Close()
+}
+
+// ReadBinary reads a stream written by WriteBinary, or a BinaryEncoder, into a
+// new collection of items of type T using the specified codec.
+func ReadBinary[T any](r io.Reader, codec ElementCodec[T]) (*Collection[T], error) {
+	decoder, out := NewBinaryDecoder(r, codec), New[T]()
+	for {
+		item, err := decoder.Decode()
+		if err == io.EOF {
+			return out, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		out.Push(item)
+	}
+}
@@ -0,0 +1,205 @@
+package collection_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wilhelm-murdoch/go-collection"
+)
+
+type Sample struct {
+	Name  string
+	Value float64
+	Tags  []string
+}
+
+func returnSamples(n int) *collection.Collection[Sample] {
+	c := collection.New[Sample]()
+	for i := 0; i < n; i++ {
+		c.Push(Sample{randomString(12), float64(i) * 1.5, []string{"a", "b"}})
+	}
+
+	return c
+}
+
+func TestCollectionGob(t *testing.T) {
+	type Envelope struct {
+		Name    string
+		Samples *collection.Collection[Sample]
+	}
+
+	in := Envelope{"samples", returnSamples(10)}
+
+	var buffer bytes.Buffer
+	assert.Nil(t, gob.NewEncoder(&buffer).Encode(in))
+
+	var out Envelope
+	assert.Nil(t, gob.NewDecoder(&buffer).Decode(&out))
+	assert.Equal(t, in.Samples.Items(), out.Samples.Items())
+
+	empty, err := collection.New[int]().GobEncode()
+	assert.Nil(t, err)
+
+	decoded := collection.New(1, 2, 3)
+	assert.Nil(t, decoded.GobDecode(empty))
+	assert.True(t, decoded.IsEmpty(), "Decoding an empty collection should clear existing items.")
+}
+
+func TestCollectionMarshalBinary(t *testing.T) {
+	in := returnSamples(5)
+
+	data, err := in.MarshalBinary()
+	assert.Nil(t, err)
+
+	out := collection.New[Sample]()
+	assert.Nil(t, out.UnmarshalBinary(data))
+	assert.Equal(t, in.Items(), out.Items())
+
+	corrupt := append([]byte(nil), data...)
+	corrupt[len(corrupt)/2] ^= 0xff
+	assert.True(t, errors.Is(out.UnmarshalBinary(corrupt), collection.ErrChecksumMismatch))
+
+	assert.True(t, errors.Is(out.UnmarshalBinary([]byte("nope")), collection.ErrInvalidHeader))
+
+	future := append([]byte(nil), data...)
+	future[4] = collection.BinaryVersion + 1
+	assert.True(t, errors.Is(out.UnmarshalBinary(future), collection.ErrUnsupportedVersion))
+}
+
+func TestCollectionBinaryStream(t *testing.T) {
+	codecs := map[string]collection.ElementCodec[Sample]{
+		"json": collection.JSONElementCodec[Sample]{},
+		"gob":  collection.GobElementCodec[Sample]{},
+	}
+
+	for name, codec := range codecs {
+		in := returnSamples(25)
+
+		var buffer bytes.Buffer
+		assert.Nil(t, in.WriteBinary(&buffer, codec), name)
+
+		out, err := collection.ReadBinary(bytes.NewReader(buffer.Bytes()), codec)
+		assert.Nil(t, err, name)
+		assert.Equal(t, in.Items(), out.Items(), name)
+
+		_, err = collection.ReadBinary(bytes.NewReader(buffer.Bytes()[:buffer.Len()-7]), codec)
+		assert.True(t, errors.Is(err, io.ErrUnexpectedEOF), "%s: truncated streams should be detected", name)
+	}
+}
+
+func TestBinaryEncoderCustomCodec(t *testing.T) {
+	codec := collection.ElementCodecFuncs[uint64]{
+		Append: func(dst []byte, n uint64) ([]byte, error) {
+			return binary.AppendUvarint(dst, n), nil
+		},
+		Decode: func(data []byte) (uint64, error) {
+			n, _ := binary.Uvarint(data)
+			return n, nil
+		},
+	}
+
+	var buffer bytes.Buffer
+	encoder := collection.NewBinaryEncoder[uint64](&buffer, codec)
+	for i := uint64(0); i < 1000; i += 7 {
+		assert.Nil(t, encoder.Encode(i))
+	}
+	assert.Nil(t, encoder.Close())
+	assert.NotNil(t, encoder.Encode(1), "Encoding after Close should fail.")
+
+	data := buffer.Bytes()
+
+	decoder := collection.NewBinaryDecoder[uint64](bytes.NewReader(data), codec)
+	count := 0
+	for {
+		n, err := decoder.Decode()
+		if err == io.EOF {
+			break
+		}
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(count*7), n)
+		count++
+	}
+	assert.Equal(t, 143, count)
+
+	data[10] ^= 0x01
+	_, err := collection.ReadBinary[uint64](bytes.NewReader(data), codec)
+	assert.True(t, errors.Is(err, collection.ErrChecksumMismatch))
+}
+
+func BenchmarkCollectionMarshalJSON(b *testing.B) {
+	c := returnSamples(1000)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		data, _ := c.MarshalJSON()
+		b.SetBytes(int64(len(data)))
+	}
+}
+
+func BenchmarkCollectionUnmarshalJSON(b *testing.B) {
+	data, _ := returnSamples(1000).MarshalJSON()
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		var out []Sample
+		json.Unmarshal(data, &out)
+	}
+}
+
+func BenchmarkCollectionMarshalBinary(b *testing.B) {
+	c := returnSamples(1000)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		data, _ := c.MarshalBinary()
+		b.SetBytes(int64(len(data)))
+	}
+}
+
+func BenchmarkCollectionUnmarshalBinary(b *testing.B) {
+	data, _ := returnSamples(1000).MarshalBinary()
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		collection.New[Sample]().UnmarshalBinary(data)
+	}
+}
+
+func BenchmarkCollectionWriteBinaryJSON(b *testing.B) {
+	c := returnSamples(1000)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		c.WriteBinary(io.Discard, collection.JSONElementCodec[Sample]{})
+	}
+}
+
+func TestBinaryDecoderCorruptLength(t *testing.T) {
+	var buffer bytes.Buffer
+	assert.Nil(t, collection.New(1, 2).WriteBinary(&buffer, collection.JSONElementCodec[int]{}))
+	header := buffer.Bytes()[:6]
+
+	lengths := map[string][]byte{
+		"overflowing": binary.AppendUvarint(nil, math.MaxUint64),
+		"oversized":   binary.AppendUvarint(nil, collection.MaxBinaryItemSize+2),
+		"truncated":   binary.AppendUvarint(nil, 1<<20),
+	}
+
+	for name, length := range lengths {
+		data := append(append(append([]byte(nil), header...), length...), "1234"...)
+
+		assert.NotPanics(t, func() {
+			_, err := collection.ReadBinary[int](bytes.NewReader(data), collection.JSONElementCodec[int]{})
+			assert.True(t, errors.Is(err, collection.ErrChecksumMismatch) || errors.Is(err, io.ErrUnexpectedEOF), name)
+		}, name)
+	}
+}
@@ -5,14 +5,21 @@ import (
 	"encoding/json"
 	"math"
 	"math/rand"
-	"reflect"
 	"sort"
 	"sync"
 	"time"
 )
 
 type Collection[T any] struct {
-	items []T
+	items      []T
+	equal      func(a, b T) bool
+	rand       *rand.Rand
+	mu         *sync.RWMutex
+	writing    *sync.Mutex
+	maxLength  int
+	overflow   OverflowPolicy
+	validators []validationRule[T]
+	keys       map[int]map[any]int
 }
 
 // New returns a new collection of type T containing the specified
@@ -25,19 +32,45 @@ func New[T any](items ...T) *Collection[T] {
 
 // Items returns the current collection's set of items.
 func (c *Collection[T]) Items() []T {
+	defer c.rlock()()
+
 	return c.items
 }
 
 // Sort sorts the collection given the provided less function. ( Chainable )
 func (c *Collection[T]) Sort(less func(i, j int) bool) *Collection[T] {
-	sort.Slice(c.items, less)
+	if c.mu == nil {
+		sort.Slice(c.items, less)
+		return c
+	}
+
+	// The less function usually reads the collection, so it runs against a
+	// permutation of a snapshot while only other writers are held off, and
+	// the result is written back under the write lock.
+	c.writing.Lock()
+	defer c.writing.Unlock()
+
+	items := c.snapshot()
+
+	order := make([]int, len(items))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return less(order[i], order[j]) })
+
+	c.mu.Lock()
+	for i, j := range order {
+		c.items[i] = items[j]
+	}
+	c.mu.Unlock()
+
 	return c
 }
 
 // Filter returns a new collection with items that have passed predicate check.
 // ( Chainable )
 func (c *Collection[T]) Filter(f func(T) bool) (out Collection[T]) {
-	for _, inner := range c.items {
+	for _, inner := range c.snapshot() {
 		if f(inner) {
 			out.Push(inner)
 		}
@@ -53,24 +86,26 @@ func (c *Collection[T]) Filter(f func(T) bool) (out Collection[T]) {
 // batch. The signature for this function is
 // `func(currentBatchIndex, currentJobIndex int, job T)`. ( Chainable )
 func (c *Collection[T]) Batch(f func(int, int, T), batchSize int) *Collection[T] {
+	items := c.snapshot()
+
 	var (
 		batches    [][]T
-		batchCount = int(math.Ceil(float64(c.Length()) / float64(batchSize)))
+		batchCount = int(math.Ceil(float64(len(items)) / float64(batchSize)))
 		wg         sync.WaitGroup
 	)
 
-	jobsCount := c.Length()
+	jobsCount := len(items)
 	if batchSize > jobsCount {
 		batchSize = jobsCount
 	}
 
 	offset, limit := 0, batchSize
 	for i := 0; i < batchCount; i++ {
-		batches = append(batches, c.items[offset:limit])
+		batches = append(batches, items[offset:limit])
 		offset, limit = limit, limit+batchSize
 
-		if limit > c.Length() {
-			limit = c.Length()
+		if limit > len(items) {
+			limit = len(items)
 		}
 	}
 
@@ -91,24 +126,33 @@ func (c *Collection[T]) Batch(f func(int, int, T), batchSize int) *Collection[T]
 // Slice returns a new collection containing a slice of the current collection
 // starting with `from` and `to` indexes. ( Chainable )
 func (c *Collection[T]) Slice(from, to int) *Collection[T] {
+	defer c.rlock()()
+
 	if from > to {
 		from = to
 	}
 
-	if to > c.Length() {
-		to = c.Length()
+	if to > len(c.items) {
+		to = len(c.items)
 	}
 
 	return New(c.items[from:to]...)
 }
 
-// Contains returns true if an item is present in the current collection. This
-// method makes use of `reflect.DeepEqual` to ensure an absolute match. If you
-// wish to check by a specific field within a slice of objects, use
-// `collection.ContainsBy` instead.
+// Contains returns true if an item is present in the current collection. Items
+// are compared as described by `collection.SetEquality`, which defaults to
+// `reflect.DeepEqual` for an absolute match. If you wish to check by a specific
+// field within a slice of objects, use `collection.ContainsBy` instead.
 func (c *Collection[T]) Contains(item T) (found bool) {
+	defer c.rlock()()
+
+	return c.contains(item)
+}
+
+func (c *Collection[T]) contains(item T) (found bool) {
+	eq := c.equality()
 	for _, inner := range c.items {
-		if reflect.DeepEqual(item, inner) {
+		if eq(item, inner) {
 			found = true
 			break
 		}
@@ -120,7 +164,7 @@ func (c *Collection[T]) Contains(item T) (found bool) {
 // specified predicate function. This is useful if you have a slice of objects
 // and you wish to check the existence of a specific field value.
 func (c *Collection[T]) ContainsBy(f func(i int, item T) bool) (found bool) {
-	for i, item := range c.items {
+	for i, item := range c.snapshot() {
 		if f(i, item) {
 			found = true
 			break
@@ -134,19 +178,24 @@ func (c *Collection[T]) ContainsBy(f func(i int, item T) bool) (found bool) {
 // current collection will be ignored. You can check for this by comparing old
 // v.s. new collection lengths.
 func (c *Collection[T]) PushDistinct(items ...T) int {
+	defer c.lock()()
+
 	for _, item := range items {
-		if !c.Contains(item) {
-			c.Push(item)
+		if !c.contains(item) {
+			c.insert(len(c.items), item)
 		}
 	}
 
-	return c.Length()
+	return len(c.items)
 }
 
 // Shift method removes the first item from the current collection, then
 // returns that item.
 func (c *Collection[T]) Shift() T {
+	defer c.lock()()
+
 	out := c.items[:1][0]
+	c.countKeys(c.items[:1], -1)
 	c.items = c.items[1:]
 
 	return out
@@ -155,15 +204,23 @@ func (c *Collection[T]) Shift() T {
 // Unshift method appends one item to the beginning of the current collection,
 // returning the new length of the collection.
 func (c *Collection[T]) Unshift(item T) int {
-	c.items = append([]T{item}, c.items...)
-	return c.Length()
+	defer c.lock()()
+
+	c.insert(0, item)
+	return len(c.items)
 }
 
 // At attempts to return the item associated with the specified index for the
 // current collection along with a boolean value stating whether or not an item
 // could be found.
 func (c *Collection[T]) At(index int) (T, bool) {
-	if index > (c.Length()-1) || index < 0 {
+	defer c.rlock()()
+
+	return c.at(index)
+}
+
+func (c *Collection[T]) at(index int) (T, bool) {
+	if index > (len(c.items)-1) || index < 0 {
 		var out T
 		return out, false
 	}
@@ -174,12 +231,16 @@ func (c *Collection[T]) At(index int) (T, bool) {
 // IsEmpty returns a boolean value describing the empty state of the current
 // collection.
 func (c *Collection[T]) IsEmpty() bool {
-	return c.Length() <= 0
+	defer c.rlock()()
+
+	return len(c.items) <= 0
 }
 
 // Empty will reset the current collection to zero items. ( Chainable )
 func (c *Collection[T]) Empty() *Collection[T] {
-	c.items = nil
+	defer c.lock()()
+
+	c.items, c.keys = nil, nil
 
 	return c
 }
@@ -188,7 +249,7 @@ func (c *Collection[T]) Empty() *Collection[T] {
 // the provided testing function. If no items satisfy the testing function,
 // a <nil> value is returned.
 func (c *Collection[T]) Find(f func(i int, item T) bool) (item T) {
-	for i, item := range c.items {
+	for i, item := range c.snapshot() {
 		if found := f(i, item); found {
 			return item
 		}
@@ -201,7 +262,7 @@ func (c *Collection[T]) Find(f func(i int, item T) bool) (item T) {
 // that satisfies the provided testing function. Otherwise, it returns -1,
 // indicating that no element passed the test.
 func (c *Collection[T]) FindIndex(f func(i int, item T) bool) int {
-	for i, item := range c.items {
+	for i, item := range c.snapshot() {
 		if found := f(i, item); found {
 			return i
 		}
@@ -213,22 +274,37 @@ func (c *Collection[T]) FindIndex(f func(i int, item T) bool) int {
 // RandomIndex returns the index associated with a random item from the current
 // collection.
 func (c *Collection[T]) RandomIndex() int {
-	rand.New(rand.NewSource(time.Now().UnixNano()))
-	return rand.Intn(c.Length() - 1)
+	defer c.lock()()
+
+	return c.intn(len(c.items) - 1)
 }
 
 // Random returns a random item from the current collection.
 func (c *Collection[T]) Random() (T, bool) {
+	defer c.lock()()
+
+	return c.at(c.intn(len(c.items)))
+}
+
+// intn returns a random number in [0, n) using the collection's source of
+// randomness, if one was configured with WithRand.
+func (c *Collection[T]) intn(n int) int {
+	if c.rand != nil {
+		return c.rand.Intn(n)
+	}
+
 	rand.New(rand.NewSource(time.Now().UnixNano()))
-	return c.At(rand.Intn(c.Length()))
+	return rand.Intn(n)
 }
 
 // LastIndexOf returns the last index at which a given item can be found in the
 // current collection, or -1 if it is not present.
 func (c *Collection[T]) LastIndexOf(item T) int {
-	index := -1
+	defer c.rlock()()
+
+	eq, index := c.equality(), -1
 	for i, inner := range c.items {
-		if reflect.DeepEqual(item, inner) {
+		if eq(item, inner) {
 			index = i
 		}
 	}
@@ -241,7 +317,7 @@ func (c *Collection[T]) LastIndexOf(item T) int {
 // accumulator function. Each successive invocation is supplied with the return
 // value returned by the previous call.
 func (c *Collection[T]) Reduce(f func(i int, item, accumulator T) T) (out T) {
-	for i, item := range c.items {
+	for i, item := range c.snapshot() {
 		out = f(i, item, out)
 	}
 
@@ -251,7 +327,9 @@ func (c *Collection[T]) Reduce(f func(i int, item, accumulator T) T) (out T) {
 // Reverse the current collection so that the first item becomes the last, the
 // second item becomes the second to last, and so on. ( Chainable )
 func (c *Collection[T]) Reverse() *Collection[T] {
-	for i1, i2 := 0, c.Length()-1; i1 < i2; i1, i2 = i1+1, i2-1 {
+	defer c.lock()()
+
+	for i1, i2 := 0, len(c.items)-1; i1 < i2; i1, i2 = i1+1, i2-1 {
 		c.items[i1], c.items[i2] = c.items[i2], c.items[i1]
 	}
 	return c
@@ -260,7 +338,7 @@ func (c *Collection[T]) Reverse() *Collection[T] {
 // Some returns a true value if at least one item within the current collection
 // resolves to true as defined by the predicate function f.
 func (c *Collection[T]) Some(f func(i int, item T) bool) bool {
-	for i, item := range c.items {
+	for i, item := range c.snapshot() {
 		if found := f(i, item); found {
 			return true
 		}
@@ -272,51 +350,58 @@ func (c *Collection[T]) Some(f func(i int, item T) bool) bool {
 // None returns a true value if no items within the current collection resolve to
 // true as defined by the predicate function f.
 func (c *Collection[T]) None(f func(i int, item T) bool) bool {
-	count := 0
-	for i, item := range c.items {
+	items, count := c.snapshot(), 0
+	for i, item := range items {
 		if found := f(i, item); !found {
 			count++
 		}
 	}
 
-	return count == c.Length()
+	return count == len(items)
 }
 
 // All returns a true value if all items within the current collection resolve to
 // true as defined by the predicate function f.
 func (c *Collection[T]) All(f func(i int, item T) bool) bool {
-	count := 0
-	for i, item := range c.items {
+	items, count := c.snapshot(), 0
+	for i, item := range items {
 		if found := f(i, item); found {
 			count++
 		}
 	}
 
-	return count == c.Length()
+	return count == len(items)
 }
 
 // Push method appends one or more items to the end of a collection, returning
 // the new length.
 func (c *Collection[T]) Push(items ...T) int {
-	c.items = append(c.items, items...)
-	return c.Length()
+	defer c.lock()()
+
+	c.insert(len(c.items), items...)
+	return len(c.items)
 }
 
 // Pop method removes the last item from the current collection and then
 // returns that item.
 func (c *Collection[T]) Pop() (out T, found bool) {
-	if c.Length() == 0 {
+	defer c.lock()()
+
+	if len(c.items) == 0 {
 		return
 	}
 
-	out = c.items[c.Length()-1]
-	c.items = c.items[0 : c.Length()-1]
+	out = c.items[len(c.items)-1]
+	c.countKeys(c.items[len(c.items)-1:], -1)
+	c.items = c.items[0 : len(c.items)-1]
 
 	return out, true
 }
 
 // Length returns number of items associated with the current collection.
 func (c *Collection[T]) Length() int {
+	defer c.rlock()()
+
 	return len(c.items)
 }
 
@@ -324,7 +409,7 @@ func (c *Collection[T]) Length() int {
 // on each array item. On each iteration f is invoked with arguments: index and
 // current item. It should return the new collection. ( Chainable )
 func (c *Collection[T]) Map(f func(int, T) T) (out Collection[T]) {
-	for i, item := range c.items {
+	for i, item := range c.snapshot() {
 		out.Push(f(i, item))
 	}
 
@@ -335,7 +420,7 @@ func (c *Collection[T]) Map(f func(int, T) T) (out Collection[T]) {
 // callback on each item. This method returns the current instance of
 // collection. ( Chainable )
 func (c *Collection[T]) Each(f func(int, T) bool) *Collection[T] {
-	for i, item := range c.items {
+	for i, item := range c.snapshot() {
 		if exit := f(i, item); exit {
 			break
 		}
@@ -347,7 +432,9 @@ func (c *Collection[T]) Each(f func(int, T) bool) *Collection[T] {
 // Concat merges two slices of items. This method returns the current instance
 // collection with the specified slice of items appended to it. ( Chainable )
 func (c *Collection[T]) Concat(items []T) *Collection[T] {
-	c.items = append(c.items, items...)
+	defer c.lock()()
+
+	c.insert(len(c.items), items...)
 	return c
 }
 
@@ -356,19 +443,17 @@ func (c *Collection[T]) Concat(items []T) *Collection[T] {
 // index greater than the size of the collectio nis specified, c.Push is used
 // instead. ( Chainable )
 func (c *Collection[T]) InsertAt(item T, index int) *Collection[T] {
+	defer c.lock()()
+
 	if index <= 0 {
-		c.Unshift(item)
-		return c
+		index = 0
 	}
 
-	if index > (c.Length() - 1) {
-		c.Push(item)
-		return c
+	if index > (len(c.items) - 1) {
+		index = len(c.items)
 	}
 
-	c.items = append(c.items[:index+1], c.items[index:]...)
-	c.items[index] = item
-
+	c.insert(index, item)
 	return c
 }
 
@@ -388,22 +473,47 @@ func (c *Collection[T]) InsertAfter(item T, index int) *Collection[T] {
 	return c.InsertAt(item, index+1)
 }
 
+// RemoveAt removes the item at the specified index, returning it along with a
+// boolean value stating whether or not there was such an item. The remaining
+// items are kept as they are, without being checked against the collection's
+// validation rules.
+func (c *Collection[T]) RemoveAt(index int) (out T, found bool) {
+	defer c.lock()()
+
+	if index < 0 || index >= len(c.items) {
+		return
+	}
+
+	out = c.items[index]
+	c.countKeys(c.items[index:index+1], -1)
+	c.items = append(c.items[:index:index], c.items[index+1:]...)
+
+	return out, true
+}
+
 // AtFirst attempts to return the first item of the collection along with a
 // boolean value stating whether or not an item could be found.
 func (c *Collection[T]) AtFirst() (T, bool) {
-	return c.At(0)
+	defer c.rlock()()
+
+	return c.at(0)
 }
 
 // AtLast attempts to return the last item of the collection along with a
 // boolean value stating whether or not an item could be found.
 func (c *Collection[T]) AtLast() (T, bool) {
-	return c.At(c.Length() - 1)
+	defer c.rlock()()
+
+	return c.at(len(c.items) - 1)
 }
 
 // Count counts the number of items in the collection that compare equal to value.
 func (c *Collection[T]) Count(item T) (count int) {
+	defer c.rlock()()
+
+	eq := c.equality()
 	for _, inner := range c.items {
-		if reflect.DeepEqual(inner, item) {
+		if eq(item, inner) {
 			count++
 		}
 	}
@@ -413,7 +523,7 @@ func (c *Collection[T]) Count(item T) (count int) {
 
 // CountBy counts the number of items in the collection for which predicate is true.
 func (c *Collection[T]) CountBy(f func(T) bool) (count int) {
-	for _, item := range c.items {
+	for _, item := range c.snapshot() {
 		if f(item) {
 			count++
 		}
@@ -428,9 +538,23 @@ func (c *Collection[T]) MarshalJSON() ([]byte, error) {
 	var buffer bytes.Buffer
 	encoder := json.NewEncoder(&buffer)
 
-	if err := encoder.Encode(c.Items()); err != nil {
+	if err := encoder.Encode(c.snapshot()); err != nil {
 		return nil, err
 	}
 
 	return buffer.Bytes(), nil
 }
+
+// UnmarshalJSON implements the Unmarshaler interface so a JSON array can be
+// unmarshalled into the current collection, replacing its items. If any item
+// breaks a validation rule, ValidationErrors are returned instead.
+func (c *Collection[T]) UnmarshalJSON(data []byte) error {
+	var items []T
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+
+	defer c.lock()()
+
+	return c.replace(items)
+}
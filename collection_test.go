@@ -149,6 +149,21 @@ func TestCollectionPop(t *testing.T) {
 	assert.False(t, ok, "Expected an emptied collection, but got %s instead.", value)
 }
 
+func TestCollectionRemoveAt(t *testing.T) {
+	c := collection.New("a", "b", "c")
+
+	value, ok := c.RemoveAt(1)
+	assert.True(t, ok)
+	assert.Equal(t, "b", value)
+	assert.Equal(t, []string{"a", "c"}, c.Items())
+
+	_, ok = c.RemoveAt(2)
+	assert.False(t, ok, "Expected no item past the end of the collection.")
+
+	_, ok = c.RemoveAt(-1)
+	assert.False(t, ok, "Expected no item at a negative index.")
+}
+
 func TestCollectionIsEmpty(t *testing.T) {
 	c := returnCollection()
 	assert.False(t, c.IsEmpty(), "Expected a collection with values, but got an empty one instead.")
@@ -465,6 +480,24 @@ func TestCollectionAll(t *testing.T) {
 	assert.True(t, c.All(func(i int, item string) bool { return c.Contains(item) }), "Expected collection to contain all items.")
 }
 
+func TestCollectionUnmarshalJSON(t *testing.T) {
+	in := Config{
+		Name:    "edge",
+		Servers: collection.New(Server{"a.example.com", 80}),
+		Tags:    collection.New("prod"),
+	}
+
+	data, err := json.Marshal(in)
+	assert.Nil(t, err)
+
+	var out Config
+	assert.Nil(t, json.Unmarshal(data, &out))
+	assert.Equal(t, in.Servers.Items(), out.Servers.Items())
+	assert.Equal(t, in.Tags.Items(), out.Tags.Items())
+
+	assert.NotNil(t, json.Unmarshal([]byte(`{"tags":{}}`), &out), "Only JSON arrays can be unmarshalled.")
+}
+
 func TestCollectionMarshalJSON(t *testing.T) {
 	var buffer strings.Builder
 	encoder := json.NewEncoder(&buffer)
@@ -0,0 +1,489 @@
+// Package collectionhttp exposes a collection as a REST resource over
+// `net/http`.
+//
+// A Handler serves the following routes relative to where it is mounted, so
+// use `http.StripPrefix` when mounting it below the root:
+//
+//	GET    /          list items, see below
+//	POST   /          append an item
+//	GET    /{index}   fetch a single item
+//	PUT    /{index}   replace an item
+//	PATCH  /{index}   update an item with a JSON Merge Patch (RFC 7396)
+//	DELETE /{index}   remove an item
+//
+// Lists are paginated with the `page` and `perPage` query parameters, filtered
+// with a `filter` expression as understood by `collection.Compile` and sorted
+// with a comma-separated `sort` parameter naming the sorters configured in
+// Options, each optionally prefixed with `-` to sort in descending order.
+// Page sizes below 1 fall back to `collection.DefaultPerPage`, and pages past
+// the end are clamped to the first empty page.
+//
+// Items which break the collection's validation rules, or do not fit within
+// its length limit, are rejected with 422 Unprocessable Entity and a body
+// listing every violation.
+//
+// Every response carries an ETag, and requests which modify the collection
+// honour the `If-Match` header: item routes compare it to the ETag of the
+// targeted item and `POST` to the ETag of the collection itself.
+package collectionhttp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/wilhelm-murdoch/go-collection"
+)
+
+// Options configures a Handler.
+type Options[T any] struct {
+	// Sorters maps the names accepted by the `sort` query parameter to the
+	// comparison functions used to order items.
+	Sorters map[string]func(a, b T) bool
+
+	// MaxPerPage caps the `perPage` query parameter. Defaults to 100.
+	MaxPerPage int
+
+	// ReadOnly rejects every request which would modify the collection.
+	ReadOnly bool
+}
+
+// Handler is an `http.Handler` serving a collection which may also be safely
+// accessed from other goroutines through View and Update.
+type Handler[T any] struct {
+	mu      sync.RWMutex
+	c       *collection.Collection[T]
+	version uint64
+	opts    Options[T]
+}
+
+// NewHandler returns a new handler serving the specified collection, which
+// should no longer be accessed directly once handed over.
+func NewHandler[T any](c *collection.Collection[T], opts Options[T]) *Handler[T] {
+	if opts.MaxPerPage < 1 {
+		opts.MaxPerPage = 100
+	}
+
+	return &Handler[T]{c: c, opts: opts}
+}
+
+// View calls the specified function with the underlying collection while
+// holding a read lock.
+func (h *Handler[T]) View(f func(c *collection.Collection[T])) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	f(h.c)
+}
+
+// Update calls the specified function with the underlying collection while
+// holding a write lock, invalidating the collection's ETag.
+func (h *Handler[T]) Update(f func(c *collection.Collection[T])) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	f(h.c)
+	h.version++
+}
+
+// ServeHTTP implements `http.Handler`.
+func (h *Handler[T]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(r.URL.Path, "/")
+
+	if path == "" {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+			h.list(w, r)
+		case http.MethodPost:
+			h.create(w, r)
+		default:
+			methodNotAllowed(w, "GET, HEAD, POST")
+		}
+		return
+	}
+
+	index, err := strconv.Atoi(path)
+	if err != nil || index < 0 {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no such item %q", path))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		h.get(w, index)
+	case http.MethodPut, http.MethodPatch:
+		h.update(w, r, index)
+	case http.MethodDelete:
+		h.delete(w, r, index)
+	default:
+		methodNotAllowed(w, "GET, HEAD, PUT, PATCH, DELETE")
+	}
+}
+
+func (h *Handler[T]) list(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	page, err := intParam(query.Get("page"), 1)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	perPage, err := intParam(query.Get("perPage"), collection.DefaultPerPage)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	switch {
+	case perPage < 1:
+		perPage = collection.DefaultPerPage
+	case perPage > h.opts.MaxPerPage:
+		perPage = h.opts.MaxPerPage
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	q := h.c.Query()
+
+	if filter := query.Get("filter"); filter != "" {
+		expression, err := collection.Compile[T](filter)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		q = q.Where(expression.Match)
+	}
+
+	if sort := query.Get("sort"); sort != "" {
+		for i, name := range strings.Split(sort, ",") {
+			descending := strings.HasPrefix(name, "-")
+			name = strings.TrimPrefix(name, "-")
+
+			less, ok := h.opts.Sorters[name]
+			if !ok {
+				writeError(w, http.StatusBadRequest, fmt.Errorf("unknown sort %q", name))
+				return
+			}
+
+			switch {
+			case i == 0 && descending:
+				q = q.OrderByDescending(less)
+			case i == 0:
+				q = q.OrderBy(less)
+			case descending:
+				q = q.ThenByDescending(less)
+			default:
+				q = q.ThenBy(less)
+			}
+		}
+	}
+
+	items := q.Collection()
+
+	// Pages past the end are all empty, so they are clamped to the first of
+	// them like perPage is clamped to MaxPerPage.
+	if last := (items.Length()+perPage-1)/perPage + 1; page > last {
+		page = last
+	}
+
+	w.Header().Set("ETag", h.collectionETag())
+	writeJSON(w, http.StatusOK, items.Paginate(page, perPage))
+}
+
+func (h *Handler[T]) create(w http.ResponseWriter, r *http.Request) {
+	if h.opts.ReadOnly {
+		methodNotAllowed(w, "GET, HEAD")
+		return
+	}
+
+	var item T
+	if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !ifMatch(r, h.collectionETag()) {
+		writeError(w, http.StatusPreconditionFailed, errors.New("collection has changed"))
+		return
+	}
+
+	length, err := h.c.TryPush(item)
+	if err != nil {
+		h.writeInvalid(w, err)
+		return
+	}
+	h.version++
+
+	// Evicting items to make room still leaves the new item last.
+	index := length - 1
+
+	// Use the original request path, as the handler may be mounted below a
+	// stripped prefix.
+	base, _, _ := strings.Cut(r.RequestURI, "?")
+	w.Header().Set("Location", fmt.Sprintf("%s/%d", strings.TrimSuffix(base, "/"), index))
+	h.writeItem(w, http.StatusCreated, item)
+}
+
+func (h *Handler[T]) get(w http.ResponseWriter, index int) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	item, ok := h.c.At(index)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no item at index %d", index))
+		return
+	}
+
+	h.writeItem(w, http.StatusOK, item)
+}
+
+func (h *Handler[T]) update(w http.ResponseWriter, r *http.Request, index int) {
+	if h.opts.ReadOnly {
+		methodNotAllowed(w, "GET, HEAD")
+		return
+	}
+
+	body, err := decodeBody(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	current, ok := h.lockedItem(w, r, index)
+	if !ok {
+		return
+	}
+
+	if r.Method == http.MethodPatch {
+		if body, err = mergePatch(current, body); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var item T
+	if err := json.Unmarshal(data, &item); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.c.TryReplaceAt(item, index); err != nil {
+		h.writeInvalid(w, err)
+		return
+	}
+	h.version++
+
+	h.writeItem(w, http.StatusOK, item)
+}
+
+func (h *Handler[T]) delete(w http.ResponseWriter, r *http.Request, index int) {
+	if h.opts.ReadOnly {
+		methodNotAllowed(w, "GET, HEAD")
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.lockedItem(w, r, index); !ok {
+		return
+	}
+
+	h.c.RemoveAt(index)
+	h.version++
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// lockedItem returns the item at the specified index, checking it against the
+// request's `If-Match` header. A response is written if false is returned.
+// The write lock must be held.
+func (h *Handler[T]) lockedItem(w http.ResponseWriter, r *http.Request, index int) (T, bool) {
+	item, ok := h.c.At(index)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no item at index %d", index))
+		return item, false
+	}
+
+	etag, err := itemETag(item)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return item, false
+	}
+
+	if !ifMatch(r, etag) {
+		writeError(w, http.StatusPreconditionFailed, fmt.Errorf("item at index %d has changed", index))
+		return item, false
+	}
+
+	return item, true
+}
+
+func (h *Handler[T]) writeItem(w http.ResponseWriter, status int, item T) {
+	etag, err := itemETag(item)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("ETag", etag)
+	writeJSON(w, status, item)
+}
+
+// writeInvalid responds with the violations held by the specified error, as
+// returned by the collection's `Try` methods, or with a server error if it
+// holds none.
+func (h *Handler[T]) writeInvalid(w http.ResponseWriter, err error) {
+	var errs collection.ValidationErrors[T]
+	if !errors.As(err, &errs) {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	type violation struct {
+		Index int    `json:"index"`
+		Rule  string `json:"rule,omitempty"`
+		Error string `json:"error"`
+	}
+
+	violations := make([]violation, len(errs))
+	for i, e := range errs {
+		violations[i] = violation{Index: e.Index, Rule: e.Rule, Error: e.Err.Error()}
+	}
+
+	writeJSON(w, http.StatusUnprocessableEntity, map[string]any{
+		"error":      err.Error(),
+		"violations": violations,
+	})
+}
+
+// collectionETag returns the ETag of the collection as a whole, which changes
+// with every modification. A lock must be held.
+func (h *Handler[T]) collectionETag() string {
+	return fmt.Sprintf(`"v%d"`, h.version)
+}
+
+// itemETag returns an ETag derived from the JSON encoding of an item.
+func itemETag(item any) (string, error) {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return "", err
+	}
+
+	hash := fnv.New64a()
+	hash.Write(data)
+
+	return fmt.Sprintf(`"%x"`, hash.Sum64()), nil
+}
+
+// ifMatch reports whether the request's `If-Match` header, if any, matches the
+// specified ETag.
+func ifMatch(r *http.Request, etag string) bool {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		return true
+	}
+
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+func decodeBody(r *http.Request) (any, error) {
+	var body any
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+// mergePatch applies the specified JSON Merge Patch to the JSON encoding of an
+// item, as described by RFC 7396.
+func mergePatch(item, patch any) (any, error) {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return nil, err
+	}
+
+	var target any
+	if err := json.Unmarshal(data, &target); err != nil {
+		return nil, err
+	}
+
+	return mergeValue(target, patch), nil
+}
+
+func mergeValue(target, patch any) any {
+	fields, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	out, ok := target.(map[string]any)
+	if !ok {
+		out = make(map[string]any)
+	}
+
+	for key, value := range fields {
+		if value == nil {
+			delete(out, key)
+		} else {
+			out[key] = mergeValue(out[key], value)
+		}
+	}
+
+	return out
+}
+
+func intParam(value string, fallback int) (int, error) {
+	if value == "" {
+		return fallback, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", value)
+	}
+
+	return n, nil
+}
+
+func methodNotAllowed(w http.ResponseWriter, allow string) {
+	w.Header().Set("Allow", allow)
+	writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
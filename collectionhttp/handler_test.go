@@ -0,0 +1,260 @@
+package collectionhttp_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wilhelm-murdoch/go-collection"
+	"github.com/wilhelm-murdoch/go-collection/collectionhttp"
+)
+
+type Book struct {
+	Title string `json:"title"`
+	Pages int    `json:"pages"`
+	Genre string `json:"genre,omitempty"`
+}
+
+func returnServer() *httptest.Server {
+	books := collection.New(
+		Book{"Dune", 412, "scifi"},
+		Book{"Emma", 474, "romance"},
+		Book{"Solaris", 204, "scifi"},
+		Book{"Ubik", 202, "scifi"},
+	)
+
+	handler := collectionhttp.NewHandler(books, collectionhttp.Options[Book]{
+		Sorters: map[string]func(a, b Book) bool{
+			"title": func(a, b Book) bool { return a.Title < b.Title },
+			"pages": func(a, b Book) bool { return a.Pages < b.Pages },
+		},
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle("/books/", http.StripPrefix("/books", handler))
+
+	return httptest.NewServer(mux)
+}
+
+func do(t *testing.T, method, url, body string, headers ...string) (*http.Response, string) {
+	request, err := http.NewRequest(method, url, strings.NewReader(body))
+	assert.Nil(t, err)
+
+	for i := 0; i+1 < len(headers); i += 2 {
+		request.Header.Set(headers[i], headers[i+1])
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	assert.Nil(t, err)
+	defer response.Body.Close()
+
+	data, err := io.ReadAll(response.Body)
+	assert.Nil(t, err)
+
+	return response, string(data)
+}
+
+func TestHandlerList(t *testing.T) {
+	server := returnServer()
+	defer server.Close()
+
+	response, body := do(t, http.MethodGet, server.URL+"/books/?filter="+
+		"genre%20%3D%3D%20%22scifi%22&sort=-pages&perPage=2&page=1", "")
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.NotEmpty(t, response.Header.Get("ETag"))
+	assert.JSONEq(t, `{
+		"items": [
+			{"title": "Dune", "pages": 412, "genre": "scifi"},
+			{"title": "Solaris", "pages": 204, "genre": "scifi"}
+		],
+		"page": 1, "perPage": 2, "total": 3, "pages": 2, "hasNext": true, "hasPrev": false
+	}`, body)
+
+	response, _ = do(t, http.MethodGet, server.URL+"/books/?filter=pages%20%3E", "")
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	response, _ = do(t, http.MethodGet, server.URL+"/books/?sort=author", "")
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	response, _ = do(t, http.MethodGet, server.URL+"/books/?page=two", "")
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	response, body = do(t, http.MethodGet, server.URL+"/books/?page=4611686018427387904&perPage=4", "")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.JSONEq(t, `{"items": [], "page": 2, "perPage": 4, "total": 4, "pages": 1, "hasNext": false, "hasPrev": true}`, body)
+
+	for _, perPage := range []string{"0", "-3"} {
+		response, body = do(t, http.MethodGet, server.URL+"/books/?page=9&perPage="+perPage, "")
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.JSONEq(t, `{"items": [], "page": 2, "perPage": 20, "total": 4, "pages": 1, "hasNext": false, "hasPrev": true}`, body)
+	}
+}
+
+func TestHandlerItems(t *testing.T) {
+	server := returnServer()
+	defer server.Close()
+
+	response, body := do(t, http.MethodGet, server.URL+"/books/1", "")
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.JSONEq(t, `{"title": "Emma", "pages": 474, "genre": "romance"}`, body)
+
+	response, _ = do(t, http.MethodGet, server.URL+"/books/9", "")
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+
+	response, _ = do(t, http.MethodGet, server.URL+"/books/emma", "")
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+
+	response, body = do(t, http.MethodPost, server.URL+"/books/", `{"title": "Kindred", "pages": 264}`)
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+	assert.Equal(t, "/books/4", response.Header.Get("Location"))
+	assert.JSONEq(t, `{"title": "Kindred", "pages": 264}`, body)
+
+	response, body = do(t, http.MethodPatch, server.URL+"/books/4", `{"genre": "scifi", "pages": null}`)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.JSONEq(t, `{"title": "Kindred", "pages": 0, "genre": "scifi"}`, body)
+
+	response, body = do(t, http.MethodPut, server.URL+"/books/4", `{"title": "Kindred", "pages": 264}`)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.JSONEq(t, `{"title": "Kindred", "pages": 264}`, body)
+
+	response, _ = do(t, http.MethodPut, server.URL+"/books/4", `{"title": `)
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	response, _ = do(t, http.MethodDelete, server.URL+"/books/0", "")
+	assert.Equal(t, http.StatusNoContent, response.StatusCode)
+
+	_, body = do(t, http.MethodGet, server.URL+"/books/?sort=title", "")
+	assert.JSONEq(t, `{
+		"items": [
+			{"title": "Emma", "pages": 474, "genre": "romance"},
+			{"title": "Kindred", "pages": 264},
+			{"title": "Solaris", "pages": 204, "genre": "scifi"},
+			{"title": "Ubik", "pages": 202, "genre": "scifi"}
+		],
+		"page": 1, "perPage": 20, "total": 4, "pages": 1, "hasNext": false, "hasPrev": false
+	}`, body)
+
+	response, _ = do(t, http.MethodPost, server.URL+"/books/0", "{}")
+	assert.Equal(t, http.StatusMethodNotAllowed, response.StatusCode)
+	assert.Equal(t, "GET, HEAD, PUT, PATCH, DELETE", response.Header.Get("Allow"))
+}
+
+func TestHandlerIfMatch(t *testing.T) {
+	server := returnServer()
+	defer server.Close()
+
+	response, _ := do(t, http.MethodGet, server.URL+"/books/0", "")
+	etag := response.Header.Get("ETag")
+
+	response, _ = do(t, http.MethodPut, server.URL+"/books/0", `{"title": "Dune Messiah", "pages": 256}`, "If-Match", `"stale"`)
+	assert.Equal(t, http.StatusPreconditionFailed, response.StatusCode)
+
+	response, _ = do(t, http.MethodPut, server.URL+"/books/0", `{"title": "Dune Messiah", "pages": 256}`, "If-Match", etag)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.NotEqual(t, etag, response.Header.Get("ETag"))
+
+	response, _ = do(t, http.MethodDelete, server.URL+"/books/0", "", "If-Match", etag)
+	assert.Equal(t, http.StatusPreconditionFailed, response.StatusCode, "Stale ETags should be rejected.")
+
+	response, _ = do(t, http.MethodGet, server.URL+"/books/", "")
+	list := response.Header.Get("ETag")
+
+	response, _ = do(t, http.MethodPost, server.URL+"/books/", `{"title": "Kindred"}`, "If-Match", list)
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+
+	response, _ = do(t, http.MethodPost, server.URL+"/books/", `{"title": "Kindred"}`, "If-Match", list)
+	assert.Equal(t, http.StatusPreconditionFailed, response.StatusCode)
+}
+
+func TestHandlerViewUpdate(t *testing.T) {
+	books := collection.New(Book{"Dune", 412, "scifi"})
+	handler := collectionhttp.NewHandler(books, collectionhttp.Options[Book]{ReadOnly: true})
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	etag := recorder.Header().Get("ETag")
+
+	handler.Update(func(c *collection.Collection[Book]) {
+		c.Push(Book{"Ubik", 202, "scifi"})
+	})
+
+	handler.View(func(c *collection.Collection[Book]) {
+		assert.Equal(t, 2, c.Length())
+	})
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.NotEqual(t, etag, recorder.Header().Get("ETag"), "Updates should change the collection's ETag.")
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/0", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
+
+func TestHandlerValidation(t *testing.T) {
+	books := collection.NewWith(
+		collection.WithItems(Book{"Dune", 412, "scifi"}, Book{"Emma", 474, "romance"}),
+		collection.WithUnique(func(b Book) string { return b.Title }),
+		collection.WithMaxLength[Book](3, collection.OverflowReject),
+		collection.WithRule("pages", func(b Book) error {
+			if b.Pages < 1 {
+				return errors.New("must have pages")
+			}
+			return nil
+		}),
+	)
+
+	server := httptest.NewServer(collectionhttp.NewHandler(books, collectionhttp.Options[Book]{}))
+	defer server.Close()
+
+	response, body := do(t, http.MethodPost, server.URL+"/", `{"title": "Dune"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, response.StatusCode)
+	assert.Empty(t, response.Header.Get("Location"))
+	assert.JSONEq(t, `{
+		"error": "collection: item 2 breaks rule \"unique\": duplicate key Dune; collection: item 2 breaks rule \"pages\": must have pages",
+		"violations": [
+			{"index": 2, "rule": "unique", "error": "duplicate key Dune"},
+			{"index": 2, "rule": "pages", "error": "must have pages"}
+		]
+	}`, body)
+
+	response, _ = do(t, http.MethodPost, server.URL+"/", `{"title": "Ubik", "pages": 202}`)
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+	assert.Equal(t, "/2", response.Header.Get("Location"))
+
+	response, body = do(t, http.MethodPost, server.URL+"/", `{"title": "Kindred", "pages": 264}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, response.StatusCode)
+	assert.Contains(t, body, `"rule":"maxLength"`)
+
+	response, _ = do(t, http.MethodPut, server.URL+"/1", `{"title": "Dune", "pages": 474}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, response.StatusCode)
+
+	response, _ = do(t, http.MethodPatch, server.URL+"/1", `{"pages": null}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, response.StatusCode)
+
+	response, _ = do(t, http.MethodPatch, server.URL+"/1", `{"pages": 475}`)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	assert.Equal(t, []Book{{"Dune", 412, "scifi"}, {"Emma", 475, "romance"}, {"Ubik", 202, ""}}, books.Items())
+}
+
+func TestHandlerDeleteInvalid(t *testing.T) {
+	numbers := collection.New(1, -2, 3).AddValidator("positive", func(n int) error {
+		if n < 0 {
+			return errors.New("must be positive")
+		}
+		return nil
+	})
+
+	server := httptest.NewServer(collectionhttp.NewHandler(numbers, collectionhttp.Options[int]{}))
+	defer server.Close()
+
+	response, _ := do(t, http.MethodDelete, server.URL+"/2", "")
+	assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	assert.Equal(t, []int{1, -2}, numbers.Items(), "Deleting should not drop items which predate a rule.")
+}
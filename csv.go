@@ -0,0 +1,377 @@
+package collection
+
+import (
+	"encoding"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSVOptions configures how collections are written to and read from CSV.
+type CSVOptions struct {
+	// Comma is the field delimiter. Defaults to ','.
+	Comma rune
+	// NoHeader disables writing, and expecting, a header row. Without a header,
+	// columns are read in struct field order.
+	NoHeader bool
+	// TimeFormat is the layout used for time.Time fields. Defaults to
+	// time.RFC3339.
+	TimeFormat string
+}
+
+// CSVError describes a problem found while reading a CSV document into a
+// collection. Row is the 1-based line of the document on which the problem was
+// found, counting the header row, if any, and every line of fields spanning
+// several. Column is the 1-based number of the offending field, or for
+// malformed CSV, the 1-based byte position within that line.
+type CSVError struct {
+	Row    int
+	Column int
+	Field  string
+	Err    error
+}
+
+func (e *CSVError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("collection: csv row %d, column %d: %v", e.Row, e.Column, e.Err)
+	}
+
+	return fmt.Sprintf("collection: csv row %d, column %d (%s): %v", e.Row, e.Column, e.Field, e.Err)
+}
+
+func (e *CSVError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// csvColumn is a single scalar field reachable from the item type by following
+// the field indexes in path, dereferencing pointers along the way.
+type csvColumn struct {
+	name string
+	path []int
+}
+
+func (o CSVOptions) withDefaults() CSVOptions {
+	if o.Comma == 0 {
+		o.Comma = ','
+	}
+
+	if o.TimeFormat == "" {
+		o.TimeFormat = time.RFC3339
+	}
+
+	return o
+}
+
+// WriteCSV writes the current collection's items to the specified writer as
+// CSV. Items must be structs, or pointers to structs, whose exported fields are
+// mapped to columns using their `csv` struct tag, falling back to the field
+// name. Nested struct fields are flattened into columns named `parent.child`
+// and fields tagged `csv:"-"` are skipped.
+func (c *Collection[T]) WriteCSV(w io.Writer, opts CSVOptions) error {
+	opts = opts.withDefaults()
+
+	columns, err := csvColumns(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Comma = opts.Comma
+
+	record := make([]string, len(columns))
+	if !opts.NoHeader {
+		for i, column := range columns {
+			record[i] = column.name
+		}
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	for _, item := range c.snapshot() {
+		v := reflect.ValueOf(item)
+		for i, column := range columns {
+			if record[i], err = formatCSVValue(csvField(v, column.path), opts); err != nil {
+				return fmt.Errorf("collection: csv column %q: %w", column.name, err)
+			}
+		}
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// ReadCSV reads CSV from the specified reader into a new collection of items of
+// type T, using the same column mapping as WriteCSV. When a header row is
+// present, columns may appear in any order and unknown columns are ignored.
+// Problems with individual values are reported as a *CSVError.
+func ReadCSV[T any](r io.Reader, opts CSVOptions) (*Collection[T], error) {
+	opts = opts.withDefaults()
+
+	columns, err := csvColumns(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(r)
+	reader.Comma = opts.Comma
+	reader.FieldsPerRecord = -1
+
+	order := make([]*csvColumn, len(columns))
+	for i := range columns {
+		order[i] = &columns[i]
+	}
+
+	out, row := New[T](), 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		row++
+
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				return nil, &CSVError{Row: parseErr.Line, Column: parseErr.Column, Err: parseErr.Err}
+			}
+			return nil, err
+		}
+
+		if row == 1 && !opts.NoHeader {
+			order = make([]*csvColumn, len(record))
+			for i, name := range record {
+				for j := range columns {
+					if columns[j].name == strings.TrimSpace(name) {
+						order[i] = &columns[j]
+					}
+				}
+			}
+			continue
+		}
+
+		var item T
+		v := reflect.ValueOf(&item).Elem()
+		allocValue(v)
+		for i, value := range record {
+			line, _ := reader.FieldPos(i)
+			if i >= len(order) {
+				return nil, &CSVError{Row: line, Column: i + 1, Err: errors.New("too many columns")}
+			}
+
+			column := order[i]
+			if column == nil || value == "" {
+				continue
+			}
+
+			if err := parseCSVValue(csvFieldForWrite(v, column.path), value, opts); err != nil {
+				return nil, &CSVError{Row: line, Column: i + 1, Field: column.name, Err: err}
+			}
+		}
+
+		out.Push(item)
+	}
+
+	return out, nil
+}
+
+func csvColumns(t reflect.Type) ([]csvColumn, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("collection: csv requires struct items, not %s", t)
+	}
+
+	return appendCSVColumns(nil, t, "", nil, map[reflect.Type]bool{}), nil
+}
+
+func appendCSVColumns(columns []csvColumn, t reflect.Type, prefix string, path []int, seen map[reflect.Type]bool) []csvColumn {
+	seen[t] = true
+	defer delete(seen, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("csv"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		fieldPath := append(append([]int(nil), path...), i)
+
+		ft := field.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+
+		if ft.Kind() == reflect.Struct && !isCSVScalar(ft) {
+			if seen[ft] {
+				continue
+			}
+
+			if field.Anonymous && field.Tag.Get("csv") == "" {
+				columns = appendCSVColumns(columns, ft, prefix, fieldPath, seen)
+			} else {
+				columns = appendCSVColumns(columns, ft, prefix+name+".", fieldPath, seen)
+			}
+			continue
+		}
+
+		columns = append(columns, csvColumn{prefix + name, fieldPath})
+	}
+
+	return columns
+}
+
+func isCSVScalar(t reflect.Type) bool {
+	return t == timeType || reflect.PointerTo(t).Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// csvField follows the specified path from v, returning an invalid value if a
+// nil pointer is encountered.
+func csvField(v reflect.Value, path []int) reflect.Value {
+	for _, i := range path {
+		if v = derefValue(v); !v.IsValid() {
+			return v
+		}
+		v = v.Field(i)
+	}
+
+	return derefValue(v)
+}
+
+// csvFieldForWrite follows the specified path from v, allocating nil pointers
+// along the way, and returns a settable value.
+func csvFieldForWrite(v reflect.Value, path []int) reflect.Value {
+	for _, i := range path {
+		v = allocValue(v).Field(i)
+	}
+
+	return allocValue(v)
+}
+
+func allocValue(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+
+	return v
+}
+
+func formatCSVValue(v reflect.Value, opts CSVOptions) (string, error) {
+	if !v.IsValid() {
+		return "", nil
+	}
+
+	if v.Type() == timeType {
+		t := v.Interface().(time.Time)
+		if t.IsZero() {
+			return "", nil
+		}
+		return t.Format(opts.TimeFormat), nil
+	}
+
+	// Types whose MarshalText has a pointer receiver are marshalled through a
+	// copy, as the field may not be addressable.
+	if !v.Type().Implements(textMarshalerType) && reflect.PointerTo(v.Type()).Implements(textMarshalerType) {
+		ptr := reflect.New(v.Type())
+		ptr.Elem().Set(v)
+		v = ptr
+	}
+
+	if marshaler, ok := v.Interface().(encoding.TextMarshaler); ok {
+		text, err := marshaler.MarshalText()
+		return string(text), err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits()), nil
+	}
+
+	return "", fmt.Errorf("unsupported type %s", v.Type())
+}
+
+func parseCSVValue(v reflect.Value, value string, opts CSVOptions) error {
+	if v.Type() == timeType {
+		t, err := time.Parse(opts.TimeFormat, value)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	if unmarshaler, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return unmarshaler.UnmarshalText([]byte(value))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+
+	return nil
+}
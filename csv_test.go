@@ -0,0 +1,145 @@
+package collection_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wilhelm-murdoch/go-collection"
+)
+
+type Location struct {
+	City    string `csv:"city"`
+	Country string `csv:"country"`
+}
+
+type Employee struct {
+	Name     string    `csv:"name"`
+	Age      int       `csv:"age"`
+	Salary   float64   `csv:"salary"`
+	Active   bool      `csv:"active"`
+	Manager  *string   `csv:"manager"`
+	Joined   time.Time `csv:"joined"`
+	Office   *Location `csv:"office"`
+	Password string    `csv:"-"`
+	Notes    string
+}
+
+func TestCollectionWriteCSV(t *testing.T) {
+	boss := "wilhelm"
+	joined := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	c := collection.New(
+		Employee{"luke", 42, 1000.5, true, &boss, joined, &Location{"Sydney", "AU"}, "secret", "a, b"},
+		Employee{Name: "rob", Age: 17},
+	)
+
+	var buffer bytes.Buffer
+	assert.Nil(t, c.WriteCSV(&buffer, collection.CSVOptions{Comma: ';', TimeFormat: "2006-01-02"}))
+
+	expected := "name;age;salary;active;manager;joined;office.city;office.country;Notes\n" +
+		"luke;42;1000.5;true;wilhelm;2020-01-02;Sydney;AU;a, b\n" +
+		"rob;17;0;false;;;;;\n"
+	assert.Equal(t, expected, buffer.String())
+}
+
+func TestReadCSV(t *testing.T) {
+	input := "age,name,office.city,manager,joined,unknown\n" +
+		"42,luke,Sydney,wilhelm,2020-01-02T00:00:00Z,x\n" +
+		"17,rob,,,,\n"
+
+	c, err := collection.ReadCSV[Employee](strings.NewReader(input), collection.CSVOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, c.Length())
+
+	luke, _ := c.At(0)
+	assert.Equal(t, "luke", luke.Name)
+	assert.Equal(t, 42, luke.Age)
+	assert.Equal(t, "Sydney", luke.Office.City)
+	assert.Equal(t, "wilhelm", *luke.Manager)
+	assert.Equal(t, 2020, luke.Joined.Year())
+
+	rob, _ := c.At(1)
+	assert.Nil(t, rob.Office, "Empty nested columns should leave pointers nil.")
+	assert.Nil(t, rob.Manager)
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	c := collection.New(
+		&Location{"Sydney", "AU"},
+		&Location{"Berlin", "DE"},
+	)
+
+	var buffer bytes.Buffer
+	assert.Nil(t, c.WriteCSV(&buffer, collection.CSVOptions{NoHeader: true, Comma: '\t'}))
+
+	out, err := collection.ReadCSV[*Location](&buffer, collection.CSVOptions{NoHeader: true, Comma: '\t'})
+	assert.Nil(t, err)
+	assert.Equal(t, c.Items(), out.Items())
+}
+
+// Version marshals itself as text through a pointer receiver.
+type Version struct {
+	Major, Minor int
+}
+
+func (v *Version) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d.%d", v.Major, v.Minor)), nil
+}
+
+func (v *Version) UnmarshalText(text []byte) error {
+	_, err := fmt.Sscanf(string(text), "%d.%d", &v.Major, &v.Minor)
+	return err
+}
+
+type Release struct {
+	Name    string  `csv:"name"`
+	Version Version `csv:"version"`
+}
+
+func TestCSVPointerTextMarshaler(t *testing.T) {
+	c := collection.New(Release{"stable", Version{1, 2}})
+
+	var buffer bytes.Buffer
+	assert.Nil(t, c.WriteCSV(&buffer, collection.CSVOptions{}))
+	assert.Equal(t, "name,version\nstable,1.2\n", buffer.String())
+
+	out, err := collection.ReadCSV[Release](&buffer, collection.CSVOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, c.Items(), out.Items())
+}
+
+func TestReadCSVErrors(t *testing.T) {
+	input := "name,age\nluke,42\nrob,seventeen\n"
+
+	_, err := collection.ReadCSV[Employee](strings.NewReader(input), collection.CSVOptions{})
+
+	var csvErr *collection.CSVError
+	if assert.True(t, errors.As(err, &csvErr)) {
+		assert.Equal(t, 3, csvErr.Row)
+		assert.Equal(t, 2, csvErr.Column)
+		assert.Equal(t, "age", csvErr.Field)
+		assert.Contains(t, err.Error(), "row 3, column 2 (age)")
+	}
+
+	_, err = collection.ReadCSV[Employee](strings.NewReader("name,Notes,age\nluke,\"line\nbreak\",x\n"), collection.CSVOptions{})
+	if assert.True(t, errors.As(err, &csvErr)) {
+		assert.Equal(t, 3, csvErr.Row, "Rows should count every line of fields spanning several.")
+		assert.Equal(t, 3, csvErr.Column)
+	}
+
+	_, err = collection.ReadCSV[Employee](strings.NewReader("name\nluke\n\"rob\n"), collection.CSVOptions{})
+	if assert.True(t, errors.As(err, &csvErr), "Malformed CSV should be reported as a CSVError.") {
+		assert.Equal(t, 3, csvErr.Row)
+	}
+
+	_, err = collection.ReadCSV[Employee](strings.NewReader("name\n\"luke\n"), collection.CSVOptions{})
+	assert.True(t, errors.As(err, &csvErr), "Malformed CSV should be reported as a CSVError.")
+
+	_, err = collection.ReadCSV[string](strings.NewReader("a\n"), collection.CSVOptions{})
+	assert.NotNil(t, err, "Only struct items are supported.")
+}
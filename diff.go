@@ -0,0 +1,456 @@
+package collection
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// EditOp identifies the kind of change described by an Edit.
+type EditOp string
+
+const (
+	// EditEqual keeps an item which is present in both collections.
+	EditEqual EditOp = "equal"
+	// EditInsert adds an item which is only present in the new collection.
+	EditInsert EditOp = "insert"
+	// EditDelete removes an item which is only present in the old collection.
+	EditDelete EditOp = "delete"
+	// EditMove relocates an item which changed position relative to its
+	// neighbours. Only produced by DiffBy.
+	EditMove EditOp = "move"
+	// EditUpdate replaces an item in place with a new version sharing its key.
+	// Only produced by DiffBy.
+	EditUpdate EditOp = "update"
+)
+
+// Edit is a single step of a Patch. OldIndex is -1 for insertions and NewIndex
+// is -1 for deletions. Item holds the new version of the item for insertions
+// and updates, and the old version otherwise.
+type Edit[T any] struct {
+	Op       EditOp `json:"op"`
+	OldIndex int    `json:"oldIndex"`
+	NewIndex int    `json:"newIndex"`
+	Item     T      `json:"item"`
+}
+
+// Patch is a sequence of edits which turns one collection into another. It can
+// be serialised to JSON, rendered with Unified and replayed with Apply.
+type Patch[T any] []Edit[T]
+
+// Diff computes a minimal edit script of insertions, deletions and equal items
+// which turns the `old` collection into the `new` one, as defined by the
+// specified equality function, using the linear space variant of Myers'
+// algorithm. ( Chainable )
+func Diff[T any](old, new *Collection[T], eq func(a, b T) bool) Patch[T] {
+	d := &differ[T]{old: old.snapshot(), new: new.snapshot(), eq: eq}
+
+	size := len(d.old) + len(d.new) + 4
+	d.fwd, d.bwd = make([]int, size), make([]int, size)
+
+	d.compare(0, len(d.old), 0, len(d.new))
+
+	return d.out
+}
+
+// differ holds the state of a single Diff. The fwd and bwd arrays are shared
+// by every middleSnake call, so memory stays linear in the input size.
+type differ[T any] struct {
+	old, new []T
+	eq       func(a, b T) bool
+	fwd, bwd []int
+	out      Patch[T]
+}
+
+func (d *differ[T]) equal(x, y int) {
+	d.out = append(d.out, Edit[T]{EditEqual, x, y, d.old[x]})
+}
+
+// compare appends the edits turning old[x:u] into new[y:v], splitting the
+// problem at a middle snake until only insertions or deletions remain.
+func (d *differ[T]) compare(x, u, y, v int) {
+	for x < u && y < v && d.eq(d.old[x], d.new[y]) {
+		d.equal(x, y)
+		x, y = x+1, y+1
+	}
+
+	suffix := 0
+	for x < u-suffix && y < v-suffix && d.eq(d.old[u-suffix-1], d.new[v-suffix-1]) {
+		suffix++
+	}
+	u, v = u-suffix, v-suffix
+
+	switch {
+	case x == u:
+		for ; y < v; y++ {
+			d.out = append(d.out, Edit[T]{EditInsert, -1, y, d.new[y]})
+		}
+	case y == v:
+		for ; x < u; x++ {
+			d.out = append(d.out, Edit[T]{EditDelete, x, -1, d.old[x]})
+		}
+	default:
+		sx, sy, ex, ey := d.middleSnake(x, u, y, v)
+
+		d.compare(x, sx, y, sy)
+		for ; sx < ex; sx, sy = sx+1, sy+1 {
+			d.equal(sx, sy)
+		}
+		d.compare(ex, u, ey, v)
+	}
+
+	for i := 0; i < suffix; i++ {
+		d.equal(u+i, v+i)
+	}
+}
+
+// middleSnake walks furthest reaching paths forwards from the start and
+// backwards from the end of old[x:u] and new[y:v] until they overlap, and
+// returns the start and end of the snake where they meet, which lies on a
+// shortest edit path.
+func (d *differ[T]) middleSnake(x, u, y, v int) (sx, sy, ex, ey int) {
+	var (
+		n, m   = u - x, v - y
+		delta  = n - m
+		odd    = delta%2 != 0
+		limit  = (n + m + 1) / 2
+		offset = limit + 1
+		fwd    = d.fwd[:2*offset+1]
+		bwd    = d.bwd[:2*offset+1]
+	)
+
+	// Both arrays hold, per diagonal k, the furthest distance travelled along
+	// old; bwd measures it from the end.
+	fwd[offset+1], bwd[offset+1] = 0, 0
+
+	for depth := 0; depth <= limit; depth++ {
+		for k := -depth; k <= depth; k += 2 {
+			var i int
+			if k == -depth || k != depth && fwd[offset+k-1] < fwd[offset+k+1] {
+				i = fwd[offset+k+1]
+			} else {
+				i = fwd[offset+k-1] + 1
+			}
+
+			j := i - k
+			si, sj := i, j
+			for i < n && j < m && d.eq(d.old[x+i], d.new[y+j]) {
+				i, j = i+1, j+1
+			}
+			fwd[offset+k] = i
+
+			if odd && k >= delta-depth+1 && k <= delta+depth-1 && i+bwd[offset+delta-k] >= n {
+				return x + si, y + sj, x + i, y + j
+			}
+		}
+
+		for k := -depth; k <= depth; k += 2 {
+			var i int
+			if k == -depth || k != depth && bwd[offset+k-1] < bwd[offset+k+1] {
+				i = bwd[offset+k+1]
+			} else {
+				i = bwd[offset+k-1] + 1
+			}
+
+			j := i - k
+			si, sj := i, j
+			for i < n && j < m && d.eq(d.old[u-i-1], d.new[v-j-1]) {
+				i, j = i+1, j+1
+			}
+			bwd[offset+k] = i
+
+			if !odd && k >= delta-depth && k <= delta+depth && i+fwd[offset+delta-k] >= n {
+				return u - i, v - j, u - si, v - sj
+			}
+		}
+	}
+
+	panic("collection: diff found no middle snake")
+}
+
+// DiffBy computes the changes between the `old` and `new` collections by
+// matching items on the key returned by the specified key function, which must
+// be unique within each collection. Items present in both are reported as equal,
+// as moved when their position changed relative to the other shared items, and
+// additionally as updated when the specified equality function reports that
+// their content changed. Deletions are listed first, followed by one or more
+// edits for every item of the new collection in order. ( Chainable )
+func DiffBy[T any, K comparable](old, new *Collection[T], key func(T) K, eq func(a, b T) bool) Patch[T] {
+	oldItems, newItems := old.snapshot(), new.snapshot()
+
+	positions := make(map[K]int, len(oldItems))
+	for i, item := range oldItems {
+		positions[key(item)] = i
+	}
+
+	var (
+		out    Patch[T]
+		shared []int
+		seen   = make(map[K]bool, len(newItems))
+	)
+
+	for _, item := range newItems {
+		k := key(item)
+		seen[k] = true
+
+		if i, found := positions[k]; found {
+			shared = append(shared, i)
+		}
+	}
+
+	for i, item := range oldItems {
+		if !seen[key(item)] {
+			out = append(out, Edit[T]{EditDelete, i, -1, item})
+		}
+	}
+
+	stable := longestIncreasing(shared)
+	for j, item := range newItems {
+		i, found := positions[key(item)]
+		if !found {
+			out = append(out, Edit[T]{EditInsert, -1, j, item})
+			continue
+		}
+
+		switch {
+		case !stable[i]:
+			out = append(out, Edit[T]{EditMove, i, j, oldItems[i]})
+		case eq(oldItems[i], item):
+			out = append(out, Edit[T]{EditEqual, i, j, oldItems[i]})
+			continue
+		}
+
+		if !eq(oldItems[i], item) {
+			out = append(out, Edit[T]{EditUpdate, i, j, item})
+		}
+	}
+
+	return out
+}
+
+// longestIncreasing returns the set of values which make up a longest strictly
+// increasing subsequence of the specified values.
+func longestIncreasing(values []int) map[int]bool {
+	var (
+		tails   []int
+		parents = make([]int, len(values))
+	)
+
+	for i, value := range values {
+		n := sort.Search(len(tails), func(j int) bool {
+			return values[tails[j]] >= value
+		})
+
+		parents[i] = -1
+		if n > 0 {
+			parents[i] = tails[n-1]
+		}
+
+		if n == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[n] = i
+		}
+	}
+
+	out := make(map[int]bool, len(tails))
+	if len(tails) > 0 {
+		for i := tails[len(tails)-1]; i >= 0; i = parents[i] {
+			out[values[i]] = true
+		}
+	}
+
+	return out
+}
+
+// HasChanges returns true if the current patch contains anything other than
+// equal items.
+func (p Patch[T]) HasChanges() bool {
+	for _, edit := range p {
+		if edit.Op != EditEqual {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Unified renders the current patch in the style of a unified diff, with each
+// change surrounded by up to `context` equal items. Deleted items are prefixed
+// with `-`, inserted and updated items with `+` and moved items with `~`.
+func (p Patch[T]) Unified(context int) string {
+	if context < 0 {
+		context = 0
+	}
+
+	var b strings.Builder
+
+	for start := 0; start < len(p); {
+		// Find the next change, then extend the hunk until the gap between two
+		// changes is larger than the surrounding context on both sides.
+		first := start
+		for first < len(p) && p[first].Op == EditEqual {
+			first++
+		}
+
+		if first == len(p) {
+			break
+		}
+
+		from, to := first-context, first
+		if from < start {
+			from = start
+		}
+
+		for to < len(p) {
+			next := to + 1
+			for next < len(p) && p[next].Op == EditEqual {
+				next++
+			}
+
+			if next == len(p) || next-to-1 > 2*context {
+				break
+			}
+			to = next
+		}
+
+		end := to + 1 + context
+		if end > len(p) {
+			end = len(p)
+		}
+
+		writeHunk(&b, p[from:end])
+		start = end
+	}
+
+	return b.String()
+}
+
+func writeHunk[T any](b *strings.Builder, hunk Patch[T]) {
+	oldStart, newStart, oldCount, newCount := -1, -1, 0, 0
+	for _, edit := range hunk {
+		if edit.OldIndex >= 0 {
+			if oldStart < 0 {
+				oldStart = edit.OldIndex
+			}
+			oldCount++
+		}
+
+		if edit.NewIndex >= 0 {
+			if newStart < 0 {
+				newStart = edit.NewIndex
+			}
+			newCount++
+		}
+	}
+
+	fmt.Fprintf(b, "@@ -%d,%d +%d,%d @@\n", oldStart+1, oldCount, newStart+1, newCount)
+	for _, edit := range hunk {
+		prefix := map[EditOp]string{
+			EditEqual:  " ",
+			EditDelete: "-",
+			EditInsert: "+",
+			EditUpdate: "+",
+			EditMove:   "~",
+		}[edit.Op]
+
+		fmt.Fprintf(b, "%s%v\n", prefix, edit.Item)
+	}
+}
+
+// Apply replays the specified patch, as produced by Diff or DiffBy, onto the
+// current collection, which must hold the old items the patch was computed
+// from, as compared by `collection.SetEquality`. An error is returned, and
+// the collection is left untouched, if the patch does not fit the collection
+// or produces items which break its validation rules.
+func (c *Collection[T]) Apply(p Patch[T]) error {
+	defer c.lock()()
+
+	// Every item of the result is produced by at least one edit, so new
+	// indices past their number cannot be valid and would only allocate.
+	produced := len(p) - deletions(p)
+
+	length := 0
+	for i, edit := range p {
+		if edit.Op != EditDelete && edit.NewIndex >= produced {
+			return fmt.Errorf("collection: patch edit %d: new index %d out of range", i, edit.NewIndex)
+		}
+
+		if edit.NewIndex >= length {
+			length = edit.NewIndex + 1
+		}
+	}
+
+	eq := c.equality()
+
+	var (
+		out    = make([]T, length)
+		filled = make([]bool, length)
+	)
+
+	for i, edit := range p {
+		needsOld := edit.Op == EditEqual || edit.Op == EditMove || edit.Op == EditDelete || edit.Op == EditUpdate
+		if needsOld && (edit.OldIndex < 0 || edit.OldIndex >= len(c.items)) {
+			return fmt.Errorf("collection: patch edit %d: old index %d out of range", i, edit.OldIndex)
+		}
+
+		// Updates carry the new version of the item, while the other edits
+		// which refer to the old items carry them as they were.
+		if needsOld && edit.Op != EditUpdate && !eq(edit.Item, c.items[edit.OldIndex]) {
+			return fmt.Errorf("collection: patch edit %d: item at old index %d does not match", i, edit.OldIndex)
+		}
+
+		if edit.Op == EditDelete {
+			continue
+		}
+
+		if edit.NewIndex < 0 {
+			return fmt.Errorf("collection: patch edit %d: missing new index", i)
+		}
+
+		switch edit.Op {
+		case EditEqual, EditMove:
+			out[edit.NewIndex] = c.items[edit.OldIndex]
+		case EditInsert, EditUpdate:
+			out[edit.NewIndex] = edit.Item
+		default:
+			return fmt.Errorf("collection: patch edit %d: unknown op %q", i, edit.Op)
+		}
+		filled[edit.NewIndex] = true
+	}
+
+	for i, ok := range filled {
+		if !ok {
+			return fmt.Errorf("collection: patch does not produce an item for index %d", i)
+		}
+	}
+
+	if kept := len(c.items) - deletions(p); kept != countOld(p) {
+		return errors.New("collection: patch does not account for every item of the collection")
+	}
+
+	return c.replace(out)
+}
+
+func deletions[T any](p Patch[T]) (n int) {
+	for _, edit := range p {
+		if edit.Op == EditDelete {
+			n++
+		}
+	}
+
+	return n
+}
+
+// countOld returns the number of distinct old items kept by the patch.
+func countOld[T any](p Patch[T]) int {
+	kept := make(map[int]bool)
+	for _, edit := range p {
+		if edit.Op != EditDelete && edit.OldIndex >= 0 {
+			kept[edit.OldIndex] = true
+		}
+	}
+
+	return len(kept)
+}
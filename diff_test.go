@@ -0,0 +1,156 @@
+package collection_test
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wilhelm-murdoch/go-collection"
+)
+
+func equalString(a, b string) bool { return a == b }
+
+func countEdits[T any](p collection.Patch[T], op collection.EditOp) (n int) {
+	for _, edit := range p {
+		if edit.Op == op {
+			n++
+		}
+	}
+
+	return n
+}
+
+// longestCommon returns the length of the longest common subsequence of the
+// specified slices.
+func longestCommon(a, b []int) int {
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			case lengths[i+1][j] > lengths[i][j+1]:
+				lengths[i][j] = lengths[i+1][j]
+			default:
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+
+	return lengths[0][0]
+}
+
+func TestDiff(t *testing.T) {
+	old := collection.New("a", "b", "c", "a", "b", "b", "a")
+	new := collection.New("c", "b", "a", "b", "a", "c")
+
+	patch := collection.Diff(old, new, equalString)
+
+	assert.True(t, patch.HasChanges())
+	assert.Equal(t, 5, countEdits(patch, collection.EditDelete)+countEdits(patch, collection.EditInsert), "Myers' algorithm should find the shortest edit script.")
+	assert.Equal(t, 4, countEdits(patch, collection.EditEqual))
+
+	assert.Nil(t, old.Apply(patch))
+	assert.Equal(t, new.Items(), old.Items(), "Applying a patch should produce the new collection.")
+}
+
+func TestDiffEdgeCases(t *testing.T) {
+	empty := collection.New[string]()
+	fruits := collection.New("apple", "orange")
+
+	assert.Empty(t, collection.Diff(empty, collection.New[string](), equalString))
+	assert.False(t, collection.Diff(fruits, collection.New("apple", "orange"), equalString).HasChanges())
+	assert.Equal(t, 2, countEdits(collection.Diff(empty, fruits, equalString), collection.EditInsert))
+	assert.Equal(t, 2, countEdits(collection.Diff(fruits, empty, equalString), collection.EditDelete))
+}
+
+func TestDiffRandomRoundTrip(t *testing.T) {
+	for i := 0; i < 500; i++ {
+		old, new := collection.New[int](), collection.New[int]()
+		for j := rand.Intn(30); j > 0; j-- {
+			old.Push(rand.Intn(5))
+		}
+		for j := rand.Intn(30); j > 0; j-- {
+			new.Push(rand.Intn(5))
+		}
+
+		patch := collection.Diff(old, new, func(a, b int) bool { return a == b })
+		assert.Equal(t, longestCommon(old.Items(), new.Items()), countEdits(patch, collection.EditEqual), "Diff should keep a longest common subsequence.")
+
+		assert.Nil(t, old.Apply(patch))
+		assert.Equal(t, append([]int{}, new.Items()...), append([]int{}, old.Items()...))
+	}
+}
+
+func TestDiffBy(t *testing.T) {
+	type Row struct {
+		ID    int
+		Value string
+	}
+
+	old := collection.New(Row{1, "a"}, Row{2, "b"}, Row{3, "c"}, Row{4, "d"})
+	new := collection.New(Row{2, "b"}, Row{3, "C"}, Row{1, "a"}, Row{5, "e"})
+
+	patch := collection.DiffBy(old, new, func(r Row) int { return r.ID }, func(a, b Row) bool { return a == b })
+
+	assert.Equal(t, collection.Patch[Row]{
+		{Op: collection.EditDelete, OldIndex: 3, NewIndex: -1, Item: Row{4, "d"}},
+		{Op: collection.EditEqual, OldIndex: 1, NewIndex: 0, Item: Row{2, "b"}},
+		{Op: collection.EditUpdate, OldIndex: 2, NewIndex: 1, Item: Row{3, "C"}},
+		{Op: collection.EditMove, OldIndex: 0, NewIndex: 2, Item: Row{1, "a"}},
+		{Op: collection.EditInsert, OldIndex: -1, NewIndex: 3, Item: Row{5, "e"}},
+	}, patch)
+
+	data, err := json.Marshal(patch)
+	assert.Nil(t, err)
+
+	var decoded collection.Patch[Row]
+	assert.Nil(t, json.Unmarshal(data, &decoded), "Patches should round-trip through JSON.")
+
+	assert.Nil(t, old.Apply(decoded))
+	assert.Equal(t, new.Items(), old.Items())
+}
+
+func TestPatchUnified(t *testing.T) {
+	old := collection.New("a", "b", "c", "d", "e", "f", "g", "h", "i")
+	new := collection.New("a", "B", "c", "d", "e", "f", "g", "h", "i", "j")
+
+	expected := "" +
+		"@@ -1,3 +1,3 @@\n" +
+		" a\n" +
+		"-b\n" +
+		"+B\n" +
+		" c\n" +
+		"@@ -9,1 +9,2 @@\n" +
+		" i\n" +
+		"+j\n"
+
+	assert.Equal(t, expected, collection.Diff(old, new, equalString).Unified(1))
+	assert.Equal(t, "", collection.Diff(old, old, equalString).Unified(3))
+}
+
+func TestCollectionApplyErrors(t *testing.T) {
+	patch := collection.Diff(collection.New("a", "b"), collection.New("a", "c"), equalString)
+
+	c := collection.New("a")
+	assert.NotNil(t, c.Apply(patch), "Patches computed against other collections should be rejected.")
+	assert.Equal(t, []string{"a"}, c.Items(), "A rejected patch should leave the collection untouched.")
+
+	c = collection.New("a", "b", "z")
+	assert.NotNil(t, c.Apply(patch), "Patches which do not account for every item should be rejected.")
+
+	assert.NotNil(t, collection.New("a").Apply(collection.Patch[string]{{Op: "bogus", OldIndex: 0, NewIndex: 0}}))
+
+	c = collection.New("a", "x")
+	assert.NotNil(t, c.Apply(patch), "Patches computed against different items should be rejected.")
+	assert.Equal(t, []string{"a", "x"}, c.Items())
+
+	huge := collection.Patch[string]{{Op: collection.EditInsert, OldIndex: -1, NewIndex: math.MaxInt - 1, Item: "a"}}
+	assert.NotNil(t, collection.New[string]().Apply(huge), "New indices past the number of edits should be rejected.")
+}
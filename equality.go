@@ -0,0 +1,91 @@
+package collection
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Equaler is implemented by types which define their own notion of equality.
+// Collections of such types use the Equal method, rather than
+// `reflect.DeepEqual`, to compare items unless SetEquality is used.
+type Equaler[T any] interface {
+	Equal(other T) bool
+}
+
+// defaultEqualities caches the default equality function of each item type.
+var defaultEqualities sync.Map
+
+// SetEquality sets the function used by Contains, LastIndexOf, Count and
+// PushDistinct to compare items of the current collection. Passing nil
+// restores the default, which uses the item type's Equal method if it
+// implements Equaler, plain `==` for types where it is equivalent to
+// `reflect.DeepEqual`, and `reflect.DeepEqual` otherwise. Collections derived
+// from the current one use the default. ( Chainable )
+func (c *Collection[T]) SetEquality(eq func(a, b T) bool) *Collection[T] {
+	defer c.lock()()
+
+	c.equal = eq
+	return c
+}
+
+// equality returns the function used to compare items of the current
+// collection.
+func (c *Collection[T]) equality() func(a, b T) bool {
+	if c.equal != nil {
+		return c.equal
+	}
+
+	return defaultEquality[T]()
+}
+
+func defaultEquality[T any]() func(a, b T) bool {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if eq, ok := defaultEqualities.Load(typ); ok {
+		return eq.(func(a, b T) bool)
+	}
+
+	var eq func(a, b T) bool
+
+	switch {
+	case typ.Implements(reflect.TypeOf((*Equaler[T])(nil)).Elem()):
+		eq = func(a, b T) bool {
+			// Nil interface values hold no method to call.
+			if equaler, ok := any(a).(Equaler[T]); ok {
+				return equaler.Equal(b)
+			}
+			return reflect.DeepEqual(a, b)
+		}
+	case shallowComparable(typ):
+		eq = func(a, b T) bool { return any(a) == any(b) }
+	default:
+		eq = func(a, b T) bool { return reflect.DeepEqual(a, b) }
+	}
+
+	defaultEqualities.Store(typ, eq)
+	return eq
+}
+
+// shallowComparable reports whether comparing values of the specified type
+// with `==` gives the same result as `reflect.DeepEqual`. This holds for types
+// made up of booleans, numbers and strings, but not for those holding
+// pointers or interfaces, which `reflect.DeepEqual` follows.
+func shallowComparable(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	case reflect.Array:
+		return shallowComparable(typ.Elem())
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			if !shallowComparable(typ.Field(i).Type) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,141 @@
+package collection_test
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wilhelm-murdoch/go-collection"
+)
+
+type Event struct {
+	ID      int
+	Tags    []string
+	Created time.Time
+}
+
+// Equal ignores when events were created and treats nil and empty tags alike.
+func (e Event) Equal(other Event) bool {
+	if e.ID != other.ID || len(e.Tags) != len(other.Tags) {
+		return false
+	}
+
+	for i := range e.Tags {
+		if e.Tags[i] != other.Tags[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestCollectionEqualer(t *testing.T) {
+	c := collection.New(Event{1, nil, time.Now()}, Event{2, []string{"a"}, time.Now()})
+
+	probe := Event{1, []string{}, time.Time{}}
+	assert.True(t, c.Contains(probe), "Types implementing Equaler should use their Equal method.")
+	assert.Equal(t, 0, c.LastIndexOf(probe))
+	assert.Equal(t, 1, c.Count(probe))
+
+	assert.Equal(t, 2, c.PushDistinct(probe, Event{2, []string{"a"}, time.Now()}))
+	assert.Equal(t, 3, c.PushDistinct(Event{3, nil, time.Now()}))
+}
+
+func TestCollectionSetEquality(t *testing.T) {
+	c := collection.New("Apple", "orange").SetEquality(func(a, b string) bool {
+		return len(a) == len(b)
+	})
+
+	assert.True(t, c.Contains("Peach"))
+	assert.Equal(t, 1, c.LastIndexOf("banana"))
+	assert.Equal(t, 1, c.Count("kiwis"))
+	assert.Equal(t, 4, c.PushDistinct("melon", "fig", "lime"))
+	assert.Equal(t, []string{"Apple", "orange", "fig", "lime"}, c.Items())
+
+	c.SetEquality(nil)
+	assert.False(t, c.Contains("Peach"), "Passing nil should restore the default equality.")
+}
+
+func TestCollectionDefaultEquality(t *testing.T) {
+	a, b := 1, 1
+	pointers := collection.New(&a)
+	assert.True(t, pointers.Contains(&b), "Pointers should still be compared by the values they point to.")
+
+	servers := collection.New(Server{"a", 1}, Server{"b", 2})
+	assert.True(t, servers.Contains(Server{"b", 2}))
+	assert.Equal(t, -1, servers.LastIndexOf(Server{"c", 3}))
+
+	slices := collection.New([]int{1, 2})
+	assert.True(t, slices.Contains([]int{1, 2}))
+
+	var values []any
+	values = append(values, 1, "a", nil)
+	assert.True(t, collection.New(values...).Contains(nil))
+}
+
+func returnStrings(n int) []string {
+	out := make([]string, n)
+	for i := range out {
+		out[i] = "item-" + strconv.Itoa(i)
+	}
+
+	return out
+}
+
+func BenchmarkCollectionContainsDeepEqual(b *testing.B) {
+	c := collection.New(returnStrings(1000)...).SetEquality(func(a, b string) bool { return reflect.DeepEqual(a, b) })
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		c.Contains("missing")
+	}
+}
+
+func BenchmarkCollectionContainsComparable(b *testing.B) {
+	c := collection.New(returnStrings(1000)...)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		c.Contains("missing")
+	}
+}
+
+func BenchmarkCollectionContainsSetEquality(b *testing.B) {
+	c := collection.New(returnStrings(1000)...).SetEquality(func(a, b string) bool { return a == b })
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		c.Contains("missing")
+	}
+}
+
+func returnEvents(n int) *collection.Collection[Event] {
+	c := collection.New[Event]()
+	for i := 0; i < n; i++ {
+		c.Push(Event{i, []string{"a"}, time.Now()})
+	}
+
+	return c
+}
+
+func BenchmarkCollectionContainsEventsDeepEqual(b *testing.B) {
+	c := returnEvents(1000).SetEquality(func(a, b Event) bool { return reflect.DeepEqual(a, b) })
+	probe := Event{ID: -1}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		c.Contains(probe)
+	}
+}
+
+func BenchmarkCollectionContainsEventsEqualer(b *testing.B) {
+	c := returnEvents(1000)
+	probe := Event{ID: -1}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		c.Contains(probe)
+	}
+}
@@ -0,0 +1,664 @@
+package collection
+
+import (
+	"container/list"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Expression is a compiled filter expression, such as
+// `status == "active" && age > 30`, which can be evaluated against items of
+// type T. Identifiers refer to struct fields by their `json` tag name, falling
+// back to the field name, and nested fields are separated by dots.
+//
+// Expressions support the comparison operators `==`, `!=`, `<`, `<=`, `>` and
+// `>=`, the logical operators `&&`, `||` and `!`, parentheses, and string,
+// number, `true`, `false` and `null` literals.
+type Expression[T any] struct {
+	source string
+	eval   func(reflect.Value) bool
+}
+
+// ExpressionError describes a problem found while compiling an expression.
+// Position is the 1-based offset of the offending character within the
+// expression.
+type ExpressionError struct {
+	Expression string
+	Position   int
+	Message    string
+}
+
+func (e *ExpressionError) Error() string {
+	return fmt.Sprintf("collection: expression %q: position %d: %s", e.Expression, e.Position, e.Message)
+}
+
+// ExpressionCacheSize is the number of compiled expressions Compile keeps,
+// evicting the least recently used once it is exceeded.
+const ExpressionCacheSize = 256
+
+type expressionCacheKey struct {
+	t      reflect.Type
+	source string
+}
+
+type expressionCacheEntry struct {
+	key        expressionCacheKey
+	expression any
+}
+
+// expressionLRU is a least recently used cache of compiled expressions, which
+// stays bounded however many distinct expressions are compiled.
+type expressionLRU struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[expressionCacheKey]*list.Element
+}
+
+func newExpressionLRU(size int) *expressionLRU {
+	return &expressionLRU{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[expressionCacheKey]*list.Element),
+	}
+}
+
+func (l *expressionLRU) load(key expressionCacheKey) (any, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	element, found := l.entries[key]
+	if !found {
+		return nil, false
+	}
+
+	l.order.MoveToFront(element)
+	return element.Value.(*expressionCacheEntry).expression, true
+}
+
+func (l *expressionLRU) store(key expressionCacheKey, expression any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if element, found := l.entries[key]; found {
+		element.Value.(*expressionCacheEntry).expression = expression
+		l.order.MoveToFront(element)
+		return
+	}
+
+	l.entries[key] = l.order.PushFront(&expressionCacheEntry{key, expression})
+
+	for l.order.Len() > l.size {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.entries, oldest.Value.(*expressionCacheEntry).key)
+	}
+}
+
+var expressionCache = newExpressionLRU(ExpressionCacheSize)
+
+// Compile parses the specified expression and resolves its fields against type
+// T, which must be a struct or a pointer to a struct. Up to
+// ExpressionCacheSize compiled expressions are cached, so compiling the same
+// expression for the same type more than once is cheap. Any problem is
+// reported as an *ExpressionError.
+func Compile[T any](source string) (*Expression[T], error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+
+	key := expressionCacheKey{t, source}
+	if cached, found := expressionCache.load(key); found {
+		return cached.(*Expression[T]), nil
+	}
+
+	p := &expressionParser{source: source, t: t}
+	if err := p.tokenize(); err != nil {
+		return nil, err
+	}
+
+	eval, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Expression[T]{source: source, eval: eval}
+	expressionCache.store(key, e)
+
+	return e, nil
+}
+
+// MustCompile is like Compile but panics if the expression cannot be compiled.
+func MustCompile[T any](source string) *Expression[T] {
+	e, err := Compile[T](source)
+	if err != nil {
+		panic(err)
+	}
+
+	return e
+}
+
+// String returns the source of the current expression.
+func (e *Expression[T]) String() string {
+	return e.source
+}
+
+// Match returns true if the specified item satisfies the current expression.
+// Its signature matches the predicates expected by `Filter` and `CountBy`.
+func (e *Expression[T]) Match(item T) bool {
+	return e.eval(reflect.ValueOf(&item).Elem())
+}
+
+// MatchAt returns true if the specified item satisfies the current expression.
+// Its signature matches the predicates expected by `Find`, `FindIndex` and
+// `ContainsBy`.
+func (e *Expression[T]) MatchAt(i int, item T) bool {
+	return e.Match(item)
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenOperator
+	tokenLeftParen
+	tokenRightParen
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value string
+	pos   int
+}
+
+type valueKind int
+
+const (
+	valueNull valueKind = iota
+	valueBool
+	valueNumber
+	valueString
+	valueObject
+)
+
+func (k valueKind) String() string {
+	return [...]string{"null", "bool", "number", "string", "object"}[k]
+}
+
+type exprValue struct {
+	kind valueKind
+	b    bool
+	n    float64
+	s    string
+}
+
+type operand struct {
+	kind valueKind
+	tok  token
+	eval func(reflect.Value) exprValue
+}
+
+type expressionParser struct {
+	source string
+	t      reflect.Type
+	tokens []token
+	next   int
+}
+
+func (p *expressionParser) errorf(pos int, format string, args ...any) error {
+	return &ExpressionError{
+		Expression: p.source,
+		Position:   pos,
+		Message:    fmt.Sprintf(format, args...),
+	}
+}
+
+func (p *expressionParser) tokenize() error {
+	src := p.source
+
+	for i := 0; i < len(src); {
+		c := src[i]
+		pos := i + 1
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			p.tokens = append(p.tokens, token{tokenLeftParen, "(", "", pos})
+			i++
+		case c == ')':
+			p.tokens = append(p.tokens, token{tokenRightParen, ")", "", pos})
+			i++
+		case c == '"' || c == '\'':
+			j := i + 1
+			for j < len(src) && src[j] != c {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+
+			if j >= len(src) {
+				return p.errorf(pos, "unterminated string")
+			}
+
+			text := src[i : j+1]
+			quoted := text
+			if c == '\'' {
+				quoted = `"` + strings.ReplaceAll(strings.ReplaceAll(text[1:len(text)-1], `\'`, `'`), `"`, `\"`) + `"`
+			}
+
+			value, err := strconv.Unquote(quoted)
+			if err != nil {
+				return p.errorf(pos, "invalid string %s", text)
+			}
+
+			p.tokens = append(p.tokens, token{tokenString, text, value, pos})
+			i = j + 1
+		case c >= '0' && c <= '9' || c == '-' || c == '.':
+			j := i + 1
+			for j < len(src) && (src[j] >= '0' && src[j] <= '9' || src[j] == '.' || src[j] == 'e' || src[j] == 'E' ||
+				(src[j] == '-' || src[j] == '+') && (src[j-1] == 'e' || src[j-1] == 'E')) {
+				j++
+			}
+
+			text := src[i:j]
+			if _, err := strconv.ParseFloat(text, 64); err != nil {
+				return p.errorf(pos, "invalid number %q", text)
+			}
+
+			p.tokens = append(p.tokens, token{tokenNumber, text, text, pos})
+			i = j
+		case c == '_' || unicode.IsLetter(rune(c)):
+			j := i + 1
+			for j < len(src) && (src[j] == '_' || src[j] == '.' || unicode.IsLetter(rune(src[j])) || unicode.IsDigit(rune(src[j]))) {
+				j++
+			}
+
+			p.tokens = append(p.tokens, token{tokenIdent, src[i:j], src[i:j], pos})
+			i = j
+		default:
+			op := ""
+			for _, candidate := range []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!"} {
+				if strings.HasPrefix(src[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+
+			if op == "" {
+				return p.errorf(pos, "unexpected character %q", c)
+			}
+
+			p.tokens = append(p.tokens, token{tokenOperator, op, op, pos})
+			i += len(op)
+		}
+	}
+
+	p.tokens = append(p.tokens, token{tokenEOF, "end of expression", "", len(src) + 1})
+	return nil
+}
+
+func (p *expressionParser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *expressionParser) advance() token {
+	tok := p.tokens[p.next]
+	if tok.kind != tokenEOF {
+		p.next++
+	}
+
+	return tok
+}
+
+func (p *expressionParser) unexpected(tok token) error {
+	if tok.kind == tokenEOF {
+		return p.errorf(tok.pos, "unexpected end of expression")
+	}
+
+	return p.errorf(tok.pos, "unexpected %q", tok.text)
+}
+
+func (p *expressionParser) parse() (func(reflect.Value) bool, error) {
+	eval, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, p.unexpected(tok)
+	}
+
+	return eval, nil
+}
+
+func (p *expressionParser) parseOr() (func(reflect.Value) bool, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenOperator && p.peek().text == "||" {
+		p.advance()
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		l := left
+		left = func(v reflect.Value) bool { return l(v) || right(v) }
+	}
+
+	return left, nil
+}
+
+func (p *expressionParser) parseAnd() (func(reflect.Value) bool, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenOperator && p.peek().text == "&&" {
+		p.advance()
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		l := left
+		left = func(v reflect.Value) bool { return l(v) && right(v) }
+	}
+
+	return left, nil
+}
+
+func (p *expressionParser) parseUnary() (func(reflect.Value) bool, error) {
+	tok := p.peek()
+
+	if tok.kind == tokenOperator && tok.text == "!" {
+		p.advance()
+
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return func(v reflect.Value) bool { return !inner(v) }, nil
+	}
+
+	if tok.kind == tokenLeftParen {
+		p.advance()
+
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if closing := p.advance(); closing.kind != tokenRightParen {
+			return nil, p.errorf(closing.pos, "expected \")\" to close \"(\" at position %d", tok.pos)
+		}
+
+		return inner, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *expressionParser) parseComparison() (func(reflect.Value) bool, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	op := p.peek()
+	switch {
+	case op.kind != tokenOperator || op.text == "&&" || op.text == "||" || op.text == "!":
+		if left.kind != valueBool {
+			return nil, p.errorf(left.tok.pos, "%q is a %s, not a condition", left.tok.text, left.kind)
+		}
+
+		return func(v reflect.Value) bool {
+			out := left.eval(v)
+			return out.kind == valueBool && out.b
+		}, nil
+	}
+	p.advance()
+
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	if left.kind != right.kind && left.kind != valueNull && right.kind != valueNull {
+		return nil, p.errorf(op.pos, "cannot compare %s %q with %s %q", left.kind, left.tok.text, right.kind, right.tok.text)
+	}
+
+	if left.kind == valueObject && right.kind != valueNull || right.kind == valueObject && left.kind != valueNull {
+		return nil, p.errorf(op.pos, "cannot compare %q with %q, only null", left.tok.text, right.tok.text)
+	}
+
+	ordered := op.text != "==" && op.text != "!="
+	if ordered && (left.kind == valueBool || left.kind == valueNull || right.kind == valueNull) {
+		return nil, p.errorf(op.pos, "operator %q is not supported for %s values", op.text, left.kind)
+	}
+
+	return func(v reflect.Value) bool {
+		return compareValues(left.eval(v), op.text, right.eval(v))
+	}, nil
+}
+
+func compareValues(a exprValue, op string, b exprValue) bool {
+	if a.kind == valueNull || b.kind == valueNull {
+		equal := a.kind == b.kind
+		if op == "==" {
+			return equal
+		}
+
+		return op == "!=" && !equal
+	}
+
+	var cmp int
+	switch a.kind {
+	case valueBool:
+		if a.b != b.b {
+			cmp = 1
+		}
+	case valueNumber:
+		cmp = compareOrdered(a.n, b.n)
+	case valueString:
+		cmp = compareOrdered(a.s, b.s)
+	}
+
+	switch op {
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	}
+
+	return cmp >= 0
+}
+
+func compareOrdered[T float64 | string](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+
+	return 0
+}
+
+func (p *expressionParser) parseOperand() (operand, error) {
+	tok := p.advance()
+
+	switch tok.kind {
+	case tokenString:
+		value := exprValue{kind: valueString, s: tok.value}
+		return operand{valueString, tok, func(reflect.Value) exprValue { return value }}, nil
+	case tokenNumber:
+		n, _ := strconv.ParseFloat(tok.value, 64)
+		value := exprValue{kind: valueNumber, n: n}
+		return operand{valueNumber, tok, func(reflect.Value) exprValue { return value }}, nil
+	case tokenIdent:
+		switch tok.value {
+		case "true", "false":
+			value := exprValue{kind: valueBool, b: tok.value == "true"}
+			return operand{valueBool, tok, func(reflect.Value) exprValue { return value }}, nil
+		case "null", "nil":
+			return operand{valueNull, tok, func(reflect.Value) exprValue { return exprValue{} }}, nil
+		}
+
+		return p.resolveField(tok)
+	}
+
+	return operand{}, p.unexpected(tok)
+}
+
+// resolveField walks the specified dotted path through the parser's type and
+// returns an operand which reads that field from an item.
+func (p *expressionParser) resolveField(tok token) (operand, error) {
+	var (
+		t     = p.t
+		steps [][]int
+	)
+
+	for _, name := range strings.Split(tok.value, ".") {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+
+		if t.Kind() != reflect.Struct {
+			return operand{}, p.errorf(tok.pos, "cannot look up field %q in %s", name, t)
+		}
+
+		field, found := lookupField(t, name)
+		if !found {
+			return operand{}, p.errorf(tok.pos, "unknown field %q in %s", name, t)
+		}
+
+		steps = append(steps, field.Index)
+		t = field.Type
+	}
+
+	nullable := false
+	for t.Kind() == reflect.Pointer {
+		t, nullable = t.Elem(), true
+	}
+
+	var kind valueKind
+	switch t.Kind() {
+	case reflect.Bool:
+		kind = valueBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		kind = valueNumber
+	case reflect.String:
+		kind = valueString
+	case reflect.Map, reflect.Slice, reflect.Interface:
+		kind = valueObject
+	default:
+		if !nullable {
+			return operand{}, p.errorf(tok.pos, "field %q of type %s cannot be compared", tok.value, t)
+		}
+		kind = valueObject
+	}
+
+	return operand{kind, tok, func(v reflect.Value) exprValue {
+		for _, index := range steps {
+			if v = derefValue(v); !v.IsValid() {
+				return exprValue{}
+			}
+
+			var err error
+			if v, err = v.FieldByIndexErr(index); err != nil {
+				return exprValue{}
+			}
+		}
+
+		if v = derefValue(v); !v.IsValid() {
+			return exprValue{}
+		}
+
+		switch kind {
+		case valueObject:
+			if (v.Kind() == reflect.Map || v.Kind() == reflect.Slice) && v.IsNil() {
+				return exprValue{}
+			}
+			return exprValue{kind: kind}
+		case valueBool:
+			return exprValue{kind: kind, b: v.Bool()}
+		case valueString:
+			return exprValue{kind: kind, s: v.String()}
+		}
+
+		switch {
+		case v.CanInt():
+			return exprValue{kind: kind, n: float64(v.Int())}
+		case v.CanUint():
+			return exprValue{kind: kind, n: float64(v.Uint())}
+		}
+
+		return exprValue{kind: kind, n: v.Float()}
+	}}, nil
+}
+
+// lookupField finds the exported field of the specified struct type whose
+// `json` tag name, or field name, matches the specified name.
+func lookupField(t reflect.Type, name string) (reflect.StructField, bool) {
+	var byName *reflect.StructField
+
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() || field.Anonymous && field.Type.Kind() == reflect.Struct {
+			continue
+		}
+
+		tag, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if tag == "-" {
+			continue
+		}
+
+		if tag == name {
+			return field, true
+		}
+
+		if field.Name == name && byName == nil {
+			f := field
+			byName = &f
+		}
+	}
+
+	if byName != nil {
+		return *byName, true
+	}
+
+	return reflect.StructField{}, false
+}
+
+func derefValue(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+
+	return v
+}
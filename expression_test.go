@@ -0,0 +1,122 @@
+package collection_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wilhelm-murdoch/go-collection"
+)
+
+type Address struct {
+	City string `json:"city"`
+}
+
+type Member struct {
+	Name    string   `json:"name"`
+	Status  string   `json:"status"`
+	Age     int      `json:"age"`
+	Score   float64  `json:"score,omitempty"`
+	Admin   bool     `json:"admin"`
+	Address *Address `json:"address"`
+	Secret  string   `json:"-"`
+}
+
+func returnMembers() *collection.Collection[Member] {
+	return collection.New(
+		Member{Name: "luke", Status: "active", Age: 42, Score: 7.5, Address: &Address{"Sydney"}},
+		Member{Name: "rob", Status: "inactive", Age: 17, Score: 3},
+		Member{Name: "peter", Status: "active", Age: 26, Admin: true, Address: &Address{"Melbourne"}},
+		Member{Name: "josh", Status: "active", Age: 31, Score: 9.25, Address: &Address{"Sydney"}},
+	)
+}
+
+func TestExpressionFilter(t *testing.T) {
+	members := returnMembers()
+
+	cases := map[string][]string{
+		`status == "active" && age > 30`:                 {"luke", "josh"},
+		`status != 'active' || admin`:                    {"rob", "peter"},
+		`!(age >= 26) `:                                  {"rob"},
+		`address.city == "Sydney" && score > 8`:          {"josh"},
+		`address == null`:                                {"rob"},
+		`Name <= "luke" && (score < 5 || score >= 9.25)`: {"josh"},
+		`admin == true`:                                  {"peter"},
+	}
+
+	for source, expected := range cases {
+		e, err := collection.Compile[Member](source)
+		assert.Nil(t, err, source)
+
+		filtered := members.Filter(e.Match)
+		var names []string
+		filtered.Each(func(i int, m Member) bool {
+			names = append(names, m.Name)
+			return false
+		})
+
+		assert.Equal(t, expected, names, source)
+	}
+}
+
+func TestExpressionPredicates(t *testing.T) {
+	members := returnMembers()
+	e := collection.MustCompile[Member](`age < 30`)
+
+	assert.Equal(t, 2, members.CountBy(e.Match))
+	assert.True(t, members.ContainsBy(e.MatchAt))
+	assert.Equal(t, "rob", members.Find(e.MatchAt).Name)
+	assert.Equal(t, `age < 30`, e.String())
+
+	pointers := collection.New(&Member{Name: "wilhelm", Age: 20})
+	assert.Equal(t, 1, pointers.CountBy(collection.MustCompile[*Member](`age == 20`).Match), "Pointer items should be supported.")
+}
+
+func TestExpressionErrors(t *testing.T) {
+	cases := map[string]int{
+		`status == "active`:   11,
+		`status === "active"`: 10,
+		`age > `:              7,
+		`email == "x"`:        1,
+		`age > "thirty"`:      5,
+		`(age > 1`:            9,
+		`status`:              1,
+		`admin < true`:        7,
+		`age > 1 age`:         9,
+		`Secret == "x"`:       1,
+		`address > 1`:         9,
+		`status # 1`:          8,
+	}
+
+	for source, position := range cases {
+		_, err := collection.Compile[Member](source)
+
+		var expressionErr *collection.ExpressionError
+		if assert.True(t, errors.As(err, &expressionErr), source) {
+			assert.Equal(t, position, expressionErr.Position, "%s: %s", source, err)
+		}
+	}
+
+	assert.Panics(t, func() { collection.MustCompile[Member](`age >`) })
+}
+
+func TestExpressionCache(t *testing.T) {
+	a, _ := collection.Compile[Member](`age > 1`)
+	b, _ := collection.Compile[Member](`age > 1`)
+	c, _ := collection.Compile[*Member](`age > 1`)
+
+	assert.Same(t, a, b, "Compiling the same expression twice should reuse the cached result.")
+	assert.NotNil(t, c)
+}
+
+func TestExpressionCacheEviction(t *testing.T) {
+	a, _ := collection.Compile[Member](`age > 2`)
+
+	for i := 0; i < collection.ExpressionCacheSize; i++ {
+		collection.MustCompile[Member](fmt.Sprintf("age > %d", i+1000))
+	}
+
+	b, _ := collection.Compile[Member](`age > 2`)
+	assert.NotSame(t, a, b, "The least recently used expression should have been evicted.")
+}
@@ -0,0 +1,416 @@
+package collection
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sort"
+)
+
+// DefaultExternalSortBuffer is the number of items an ExternalSorter holds in
+// memory before spilling them to disk, unless configured otherwise.
+const DefaultExternalSortBuffer = 100000
+
+// DefaultExternalSortFanIn is the number of runs an ExternalSorter merges at
+// once, unless configured otherwise.
+const DefaultExternalSortFanIn = 64
+
+// ExternalSortOptions configures an ExternalSorter.
+type ExternalSortOptions[T any] struct {
+	// Codec encodes items in the temporary run files. Defaults to
+	// GobElementCodec.
+	Codec ElementCodec[T]
+
+	// MaxItems is the memory budget, as the number of items buffered before a
+	// sorted run is spilled to disk. Defaults to DefaultExternalSortBuffer.
+	MaxItems int
+
+	// MaxFanIn is the number of runs merged at once, which bounds the number of
+	// files held open. When there are more runs, they are first merged into
+	// fewer, longer ones over several passes. Defaults to
+	// DefaultExternalSortFanIn, and values below 2 are raised to 2.
+	MaxFanIn int
+
+	// Dir is the directory temporary run files are created in. Defaults to
+	// os.TempDir.
+	Dir string
+}
+
+// ExternalSorter sorts more items than fit in memory. Items are buffered until
+// the memory budget is reached, at which point they are sorted and spilled to
+// a temporary file as a run. Sort then merges every run back together, in
+// several passes if there are more than MaxFanIn. The sort is stable.
+type ExternalSorter[T any] struct {
+	less   func(a, b T) bool
+	opts   ExternalSortOptions[T]
+	buffer []T
+	runs   []string
+	done   bool
+}
+
+// NewExternalSorter returns a new external sorter ordering items with the
+// specified less function.
+func NewExternalSorter[T any](less func(a, b T) bool, opts ExternalSortOptions[T]) *ExternalSorter[T] {
+	if opts.Codec == nil {
+		opts.Codec = GobElementCodec[T]{}
+	}
+
+	if opts.MaxItems < 1 {
+		opts.MaxItems = DefaultExternalSortBuffer
+	}
+
+	switch {
+	case opts.MaxFanIn == 0:
+		opts.MaxFanIn = DefaultExternalSortFanIn
+	case opts.MaxFanIn < 2:
+		opts.MaxFanIn = 2
+	}
+
+	return &ExternalSorter[T]{less: less, opts: opts}
+}
+
+// Add adds the specified items to the sorter, spilling a run to disk whenever
+// the memory budget is reached. If an error is returned, every temporary file
+// has been removed and the sorter can no longer be used.
+func (s *ExternalSorter[T]) Add(items ...T) error {
+	if s.done {
+		return errors.New("collection: add on finished external sorter")
+	}
+
+	for _, item := range items {
+		s.buffer = append(s.buffer, item)
+
+		if len(s.buffer) >= s.opts.MaxItems {
+			if err := s.spill(); err != nil {
+				s.Close()
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// AddCollection adds every item of the specified collection to the sorter.
+func (s *ExternalSorter[T]) AddCollection(c *Collection[T]) error {
+	return s.Add(c.snapshot()...)
+}
+
+// Runs returns the number of runs spilled to disk so far.
+func (s *ExternalSorter[T]) Runs() int {
+	return len(s.runs)
+}
+
+// spill sorts the buffered items and writes them to a new run file.
+func (s *ExternalSorter[T]) spill() error {
+	s.sortBuffer()
+
+	file, err := os.CreateTemp(s.opts.Dir, "collection-sort-*")
+	if err != nil {
+		return err
+	}
+	s.runs = append(s.runs, file.Name())
+
+	if err := New(s.buffer...).WriteBinary(file, s.opts.Codec); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	s.buffer = s.buffer[:0]
+	return nil
+}
+
+func (s *ExternalSorter[T]) sortBuffer() {
+	sort.SliceStable(s.buffer, func(i, j int) bool {
+		return s.less(s.buffer[i], s.buffer[j])
+	})
+}
+
+// Sort finishes adding items and returns an iterator over every item in sorted
+// order, merging the spilled runs with any items still held in memory. The
+// iterator must be closed to remove the temporary files.
+func (s *ExternalSorter[T]) Sort() (*ExternalIterator[T], error) {
+	if s.done {
+		return nil, errors.New("collection: sort on finished external sorter")
+	}
+	s.done = true
+
+	for len(s.runs) > s.opts.MaxFanIn {
+		if err := s.mergePass(); err != nil {
+			s.Close()
+			return nil, err
+		}
+	}
+
+	s.sortBuffer()
+
+	return s.merge(s.runs, s.buffer)
+}
+
+// mergePass merges every group of up to MaxFanIn consecutive runs into a
+// single run, keeping their order so that the sort remains stable.
+func (s *ExternalSorter[T]) mergePass() error {
+	var merged []string
+
+	for len(s.runs) > 0 {
+		n := s.opts.MaxFanIn
+		if n > len(s.runs) {
+			n = len(s.runs)
+		}
+
+		name, err := s.mergeRuns(s.runs[:n])
+		if err != nil {
+			s.runs = append(merged, s.runs...)
+			return err
+		}
+
+		merged, s.runs = append(merged, name), s.runs[n:]
+	}
+
+	s.runs = merged
+	return nil
+}
+
+// mergeRuns merges the specified runs into a new run file, removing them.
+func (s *ExternalSorter[T]) mergeRuns(runs []string) (string, error) {
+	file, err := os.CreateTemp(s.opts.Dir, "collection-sort-*")
+	if err != nil {
+		return "", err
+	}
+
+	fail := func(err error) (string, error) {
+		file.Close()
+		os.Remove(file.Name())
+		return "", err
+	}
+
+	it, err := s.merge(runs, nil)
+	if err != nil {
+		return fail(err)
+	}
+	defer it.Close()
+
+	encoder := NewBinaryEncoder(file, s.opts.Codec)
+	for {
+		item, err := it.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return fail(err)
+		}
+
+		if err := encoder.Encode(item); err != nil {
+			return fail(err)
+		}
+	}
+
+	if err := encoder.Close(); err != nil {
+		return fail(err)
+	}
+
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+
+	return file.Name(), nil
+}
+
+// merge returns an iterator merging the specified runs, followed by the
+// specified sorted items held in memory. The runs are removed when the
+// iterator is closed, including if an error is returned.
+func (s *ExternalSorter[T]) merge(runs []string, buffer []T) (*ExternalIterator[T], error) {
+	it := &ExternalIterator[T]{runs: runs, buffer: buffer}
+	it.queue = NewPriorityQueue(func(a, b mergeHead[T]) bool {
+		if s.less(a.item, b.item) {
+			return true
+		}
+
+		// Earlier runs hold earlier items, which keeps the merge stable.
+		return !s.less(b.item, a.item) && a.run < b.run
+	})
+
+	for i, name := range runs {
+		file, err := os.Open(name)
+		if err != nil {
+			it.Close()
+			return nil, err
+		}
+
+		it.files = append(it.files, file)
+		it.decoders = append(it.decoders, NewBinaryDecoder(file, s.opts.Codec))
+
+		if err := it.advance(i); err != nil {
+			it.Close()
+			return nil, err
+		}
+	}
+
+	it.advance(len(runs))
+
+	return it, nil
+}
+
+// Close discards the sorter, removing every temporary file. It is only needed
+// when Sort is never called.
+func (s *ExternalSorter[T]) Close() error {
+	s.done = true
+	s.buffer = nil
+
+	return removeRuns(s.runs)
+}
+
+func removeRuns(runs []string) (err error) {
+	for _, name := range runs {
+		if removeErr := os.Remove(name); removeErr != nil && !errors.Is(removeErr, os.ErrNotExist) && err == nil {
+			err = removeErr
+		}
+	}
+
+	return err
+}
+
+// mergeHead is the next item of a run during a k-way merge.
+type mergeHead[T any] struct {
+	item T
+	run  int
+}
+
+// ExternalIterator yields the sorted items of an ExternalSorter by merging its
+// runs.
+type ExternalIterator[T any] struct {
+	runs     []string
+	files    []*os.File
+	decoders []*BinaryDecoder[T]
+	buffer   []T
+	queue    *PriorityQueue[mergeHead[T]]
+	closed   bool
+}
+
+// advance pushes the next item of the specified run onto the merge queue, if
+// there is one. The run after the last file is the in-memory buffer.
+func (it *ExternalIterator[T]) advance(run int) error {
+	if run == len(it.decoders) {
+		if len(it.buffer) > 0 {
+			it.queue.Push(mergeHead[T]{it.buffer[0], run})
+			it.buffer = it.buffer[1:]
+		}
+		return nil
+	}
+
+	item, err := it.decoders[run].Decode()
+	if err == io.EOF {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	it.queue.Push(mergeHead[T]{item, run})
+	return nil
+}
+
+// Next returns the next item in sorted order, or io.EOF once every item has
+// been returned. The iterator is closed automatically if an error is returned.
+func (it *ExternalIterator[T]) Next() (out T, err error) {
+	head, found := it.queue.Pop()
+	if !found {
+		if err := it.Close(); err != nil {
+			return out, err
+		}
+		return out, io.EOF
+	}
+
+	if err := it.advance(head.run); err != nil {
+		it.Close()
+		return out, err
+	}
+
+	return head.item, nil
+}
+
+// NextBatch returns a collection of up to `size` items in sorted order, or
+// io.EOF once every item has been returned. Sizes below 1 are treated as 1.
+// ( Chainable )
+func (it *ExternalIterator[T]) NextBatch(size int) (*Collection[T], error) {
+	if size < 1 {
+		size = 1
+	}
+
+	out := New[T]()
+	for out.Length() < size {
+		item, err := it.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		out.Push(item)
+	}
+
+	if out.IsEmpty() {
+		return nil, io.EOF
+	}
+
+	return out, nil
+}
+
+// Close closes and removes every temporary file. It is safe to call more than
+// once.
+func (it *ExternalIterator[T]) Close() error {
+	if it.closed {
+		return nil
+	}
+	it.closed = true
+
+	for _, file := range it.files {
+		file.Close()
+	}
+
+	it.queue = NewPriorityQueue(it.queue.h.less)
+	it.buffer = nil
+
+	return removeRuns(it.runs)
+}
+
+// ExternalSort sorts the items of the specified collection using an
+// ExternalSorter, calling the specified function with successive chunks of
+// up to `chunkSize` sorted items. Iteration stops at the first error, which
+// is returned.
+func ExternalSort[T any](c *Collection[T], less func(a, b T) bool, chunkSize int, opts ExternalSortOptions[T], f func(*Collection[T]) error) error {
+	sorter := NewExternalSorter(less, opts)
+	if err := sorter.AddCollection(c); err != nil {
+		return err
+	}
+
+	it, err := sorter.Sort()
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+
+	for {
+		chunk, err := it.NextBatch(chunkSize)
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err := f(chunk); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package collection_test
+
+import (
+	"errors"
+	"io"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wilhelm-murdoch/go-collection"
+)
+
+func TestExternalSorter(t *testing.T) {
+	dir := t.TempDir()
+
+	sorter := collection.NewExternalSorter(lessInt, collection.ExternalSortOptions[int]{MaxItems: 100, Dir: dir})
+
+	var expected []int
+	for i := 0; i < 1050; i++ {
+		n := rand.Intn(500)
+		expected = append(expected, n)
+		assert.Nil(t, sorter.Add(n))
+	}
+	sort.Ints(expected)
+
+	assert.Equal(t, 10, sorter.Runs(), "Runs should be spilled whenever the memory budget is reached.")
+
+	it, err := sorter.Sort()
+	assert.Nil(t, err)
+
+	var actual []int
+	for {
+		n, err := it.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.Nil(t, err)
+		actual = append(actual, n)
+	}
+
+	assert.Equal(t, expected, actual)
+
+	entries, err := os.ReadDir(dir)
+	assert.Nil(t, err)
+	assert.Empty(t, entries, "Temporary files should be removed once iteration completes.")
+}
+
+func TestExternalSortStable(t *testing.T) {
+	c := collection.New[Server]()
+	for i := 0; i < 200; i++ {
+		c.Push(Server{"host", i % 3})
+	}
+
+	var ports, sizes []int
+	err := collection.ExternalSort(c, func(a, b Server) bool { return a.Port < b.Port }, 64, collection.ExternalSortOptions[Server]{
+		Codec:    collection.JSONElementCodec[Server]{},
+		MaxItems: 16,
+		Dir:      t.TempDir(),
+	}, func(chunk *collection.Collection[Server]) error {
+		assert.LessOrEqual(t, chunk.Length(), 64)
+		sizes = append(sizes, chunk.Length())
+		chunk.Each(func(i int, s Server) bool {
+			ports = append(ports, s.Port)
+			return false
+		})
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Len(t, ports, 200)
+	assert.True(t, sort.IntsAreSorted(ports))
+	assert.Equal(t, []int{64, 64, 64, 8}, sizes)
+}
+
+func TestExternalSortMultiPass(t *testing.T) {
+	dir := t.TempDir()
+
+	sorter := collection.NewExternalSorter(func(a, b Server) bool { return a.Port < b.Port }, collection.ExternalSortOptions[Server]{
+		Codec:    collection.JSONElementCodec[Server]{},
+		MaxItems: 4,
+		MaxFanIn: 3,
+		Dir:      dir,
+	})
+
+	for i := 0; i < 100; i++ {
+		assert.Nil(t, sorter.Add(Server{strconv.Itoa(i), rand.Intn(5)}))
+	}
+	assert.Equal(t, 25, sorter.Runs())
+
+	it, err := sorter.Sort()
+	assert.Nil(t, err)
+
+	entries, err := os.ReadDir(dir)
+	assert.Nil(t, err)
+	assert.LessOrEqual(t, len(entries), 3, "Runs should be merged down to the fan-in before the final merge.")
+
+	var actual []Server
+	for {
+		s, err := it.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.Nil(t, err)
+		actual = append(actual, s)
+	}
+
+	assert.Len(t, actual, 100)
+	assert.True(t, sort.SliceIsSorted(actual, func(i, j int) bool {
+		if actual[i].Port != actual[j].Port {
+			return actual[i].Port < actual[j].Port
+		}
+
+		// Items sharing a port should keep the order they were added in.
+		a, _ := strconv.Atoi(actual[i].Host)
+		b, _ := strconv.Atoi(actual[j].Host)
+		return a < b
+	}))
+
+	entries, err = os.ReadDir(dir)
+	assert.Nil(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestExternalSortCleanup(t *testing.T) {
+	dir := t.TempDir()
+	failure := errors.New("failure")
+
+	err := collection.ExternalSort(collection.New(5, 4, 3, 2, 1), lessInt, 2, collection.ExternalSortOptions[int]{MaxItems: 2, Dir: dir},
+		func(chunk *collection.Collection[int]) error { return failure })
+	assert.Equal(t, failure, err)
+
+	entries, err := os.ReadDir(dir)
+	assert.Nil(t, err)
+	assert.Empty(t, entries, "Temporary files should be removed when iteration stops early.")
+
+	sorter := collection.NewExternalSorter(lessInt, collection.ExternalSortOptions[int]{MaxItems: 2, Dir: dir})
+	assert.Nil(t, sorter.Add(3, 2, 1))
+	assert.Nil(t, sorter.Close())
+	assert.NotNil(t, sorter.Add(4), "Closed sorters should reject new items.")
+
+	entries, err = os.ReadDir(dir)
+	assert.Nil(t, err)
+	assert.Empty(t, entries)
+
+	sorter = collection.NewExternalSorter(lessInt, collection.ExternalSortOptions[int]{MaxItems: 2, Dir: dir + "/missing"})
+	assert.NotNil(t, sorter.Add(3, 2, 1), "Spilling into a missing directory should fail.")
+}
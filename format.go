@@ -0,0 +1,266 @@
+package collection
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxStringItems is the number of items String prints before eliding the rest
+// of the collection. Use the `%+v` verb to print every item.
+const MaxStringItems = 10
+
+// String returns a short, human readable representation of the current
+// collection, eliding items beyond MaxStringItems.
+func (c *Collection[T]) String() string {
+	var b strings.Builder
+
+	items := c.snapshot()
+
+	b.WriteByte('[')
+	for i, item := range items {
+		if i >= MaxStringItems {
+			fmt.Fprintf(&b, " ... %d more", len(items)-i)
+			break
+		}
+
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		fmt.Fprint(&b, item)
+	}
+	b.WriteByte(']')
+
+	return b.String()
+}
+
+// Format implements the fmt.Formatter interface. The `%v` and `%s` verbs print
+// the same output as String, while `%+v` prints every item on its own line
+// along with its index and field names. Any other verb is applied to the
+// current collection's items as a slice.
+func (c *Collection[T]) Format(f fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && f.Flag('+'):
+		items := c.snapshot()
+
+		fmt.Fprintf(f, "Collection[%s] (%d items)", reflect.TypeOf((*T)(nil)).Elem(), len(items))
+		for i, item := range items {
+			fmt.Fprintf(f, "\n  %d: %+v", i, item)
+		}
+	case verb == 'v' && !f.Flag('#'), verb == 's':
+		io.WriteString(f, c.String())
+	default:
+		fmt.Fprintf(f, fmt.FormatString(f, verb), c.snapshot())
+	}
+}
+
+// TableStyle describes the borders drawn by Table.
+type TableStyle int
+
+const (
+	// TableASCII draws borders using `+`, `-` and `|`.
+	TableASCII TableStyle = iota
+	// TableMarkdown renders a GitHub flavoured Markdown table.
+	TableMarkdown
+	// TableBox draws borders using Unicode box-drawing characters.
+	TableBox
+)
+
+// TableOptions configures how Table renders a collection.
+type TableOptions struct {
+	// Style selects the borders drawn around cells.
+	Style TableStyle
+	// Columns selects, and orders, the struct fields to render by name. All
+	// exported fields are rendered when empty.
+	Columns []string
+	// MaxWidth truncates cells longer than the specified number of characters.
+	// Zero means no limit.
+	MaxWidth int
+	// MaxRows limits the number of rows rendered, noting how many were left
+	// out. Zero means no limit.
+	MaxRows int
+}
+
+type tableBorders struct {
+	top, header, bottom [4]string
+	vertical            string
+}
+
+var tableStyles = map[TableStyle]tableBorders{
+	TableASCII: {
+		top:      [4]string{"+", "-", "+", "+"},
+		header:   [4]string{"+", "-", "+", "+"},
+		bottom:   [4]string{"+", "-", "+", "+"},
+		vertical: "|",
+	},
+	TableMarkdown: {
+		header:   [4]string{"|", "-", "|", "|"},
+		vertical: "|",
+	},
+	TableBox: {
+		top:      [4]string{"┌", "─", "┬", "┐"},
+		header:   [4]string{"├", "─", "┼", "┤"},
+		bottom:   [4]string{"└", "─", "┴", "┘"},
+		vertical: "│",
+	},
+}
+
+// Table renders the current collection's items to the specified writer as a
+// table with aligned columns, one row per item. Struct items, or pointers to
+// structs, get one column per exported field while any other item is rendered
+// in a single `Value` column.
+func (c *Collection[T]) Table(w io.Writer, opts TableOptions) error {
+	borders, found := tableStyles[opts.Style]
+	if !found {
+		return fmt.Errorf("collection: unknown table style %d", opts.Style)
+	}
+
+	headers, cells, err := tableCells(c, opts)
+	if err != nil {
+		return err
+	}
+
+	widths := make([]int, len(headers))
+	for _, row := range append([][]string{headers}, cells...) {
+		for i, cell := range row {
+			if n := utf8.RuneCountInString(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	var b strings.Builder
+
+	rule := func(border [4]string) {
+		if border[0] == "" {
+			return
+		}
+
+		b.WriteString(border[0])
+		for i, width := range widths {
+			if i > 0 {
+				b.WriteString(border[2])
+			}
+			b.WriteString(strings.Repeat(border[1], width+2))
+		}
+		b.WriteString(border[3])
+		b.WriteByte('\n')
+	}
+
+	line := func(row []string) {
+		b.WriteString(borders.vertical)
+		for i, cell := range row {
+			b.WriteByte(' ')
+			b.WriteString(cell)
+			b.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)))
+			b.WriteByte(' ')
+			b.WriteString(borders.vertical)
+		}
+		b.WriteByte('\n')
+	}
+
+	rule(borders.top)
+	line(headers)
+	rule(borders.header)
+	for _, row := range cells {
+		line(row)
+	}
+
+	if len(cells) > 0 {
+		rule(borders.bottom)
+	}
+
+	switch hidden := c.Length() - len(cells); {
+	case hidden == 1:
+		b.WriteString("... 1 more row\n")
+	case hidden > 1:
+		fmt.Fprintf(&b, "... %d more rows\n", hidden)
+	}
+
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+func tableCells[T any](c *Collection[T], opts TableOptions) ([]string, [][]string, error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var (
+		headers []string
+		paths   [][]int
+	)
+
+	if t.Kind() == reflect.Struct {
+		fields := make(map[string][]int)
+		for _, field := range reflect.VisibleFields(t) {
+			if field.IsExported() && !field.Anonymous {
+				fields[field.Name] = field.Index
+				if len(opts.Columns) == 0 {
+					headers = append(headers, field.Name)
+				}
+			}
+		}
+
+		if len(opts.Columns) > 0 {
+			headers = opts.Columns
+		}
+
+		for _, name := range headers {
+			path, found := fields[name]
+			if !found {
+				return nil, nil, fmt.Errorf("collection: unknown table column %q", name)
+			}
+			paths = append(paths, path)
+		}
+	} else {
+		headers, paths = []string{"Value"}, [][]int{nil}
+	}
+
+	rows := c.snapshot()
+	if opts.MaxRows > 0 && len(rows) > opts.MaxRows {
+		rows = rows[:opts.MaxRows]
+	}
+
+	cells := make([][]string, len(rows))
+	for i, item := range rows {
+		cells[i] = make([]string, len(paths))
+		for j, path := range paths {
+			cells[i][j] = tableCell(reflect.ValueOf(&item).Elem(), path, opts)
+		}
+	}
+
+	return headers, cells, nil
+}
+
+func tableCell(v reflect.Value, path []int, opts TableOptions) string {
+	for _, index := range path {
+		if v = derefValue(v); !v.IsValid() {
+			return "<nil>"
+		}
+		v = v.Field(index)
+	}
+
+	cell := "<nil>"
+	if v := derefValue(v); v.IsValid() {
+		cell = fmt.Sprint(v.Interface())
+	}
+
+	cell = strings.NewReplacer("\r", " ", "\n", " ", "\t", " ").Replace(cell)
+	if opts.Style == TableMarkdown {
+		cell = strings.ReplaceAll(cell, "|", `\|`)
+	}
+
+	if opts.MaxWidth > 0 && utf8.RuneCountInString(cell) > opts.MaxWidth {
+		runes := []rune(cell)
+		if opts.MaxWidth == 1 {
+			return "…"
+		}
+		cell = string(runes[:opts.MaxWidth-1]) + "…"
+	}
+
+	return cell
+}
@@ -0,0 +1,110 @@
+package collection_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wilhelm-murdoch/go-collection"
+)
+
+type Fruit struct {
+	Name   string
+	Colour string
+	Price  float64
+	Origin *string
+}
+
+func returnFruits() *collection.Collection[Fruit] {
+	origin := "Spain"
+	return collection.New(
+		Fruit{"apple", "red", 1.5, nil},
+		Fruit{"orange", "orange", 0.75, &origin},
+		Fruit{"strawberry", "red", 3, nil},
+	)
+}
+
+func TestCollectionString(t *testing.T) {
+	assert.Equal(t, "[1 2 3]", collection.New(1, 2, 3).String())
+	assert.Equal(t, "[]", collection.New[int]().String())
+
+	numbers := collection.New[int]()
+	for i := 0; i < 15; i++ {
+		numbers.Push(i)
+	}
+	assert.Equal(t, "[0 1 2 3 4 5 6 7 8 9 ... 5 more]", numbers.String(), "Long collections should be elided.")
+}
+
+func TestCollectionFormat(t *testing.T) {
+	c := collection.New(1, 2, 3)
+
+	assert.Equal(t, "[1 2 3]", fmt.Sprintf("%v", c))
+	assert.Equal(t, "[1 2 3]", fmt.Sprint(c))
+	assert.Equal(t, "[01 02 03]", fmt.Sprintf("%02d", c), "Other verbs should apply to each item.")
+	assert.Equal(t, "[]int{1, 2, 3}", fmt.Sprintf("%#v", c))
+	assert.Equal(t, "Collection[collection_test.Server] (2 items)\n  0: {Host:a Port:1}\n  1: {Host:b Port:2}",
+		fmt.Sprintf("%+v", collection.New(Server{"a", 1}, Server{"b", 2})))
+}
+
+func TestCollectionTableASCII(t *testing.T) {
+	var buffer bytes.Buffer
+	assert.Nil(t, returnFruits().Table(&buffer, collection.TableOptions{}))
+
+	expected := "" +
+		"+------------+--------+-------+--------+\n" +
+		"| Name       | Colour | Price | Origin |\n" +
+		"+------------+--------+-------+--------+\n" +
+		"| apple      | red    | 1.5   | <nil>  |\n" +
+		"| orange     | orange | 0.75  | Spain  |\n" +
+		"| strawberry | red    | 3     | <nil>  |\n" +
+		"+------------+--------+-------+--------+\n"
+	assert.Equal(t, expected, buffer.String())
+}
+
+func TestCollectionTableMarkdown(t *testing.T) {
+	var buffer bytes.Buffer
+	assert.Nil(t, returnFruits().Table(&buffer, collection.TableOptions{
+		Style:    collection.TableMarkdown,
+		Columns:  []string{"Price", "Name"},
+		MaxWidth: 6,
+		MaxRows:  2,
+	}))
+
+	expected := "" +
+		"| Price | Name   |\n" +
+		"|-------|--------|\n" +
+		"| 1.5   | apple  |\n" +
+		"| 0.75  | orange |\n" +
+		"... 1 more row\n"
+	assert.Equal(t, expected, buffer.String())
+
+	buffer.Reset()
+	assert.Nil(t, returnFruits().Table(&buffer, collection.TableOptions{Style: collection.TableMarkdown, MaxRows: 1}))
+	assert.True(t, strings.HasSuffix(buffer.String(), "... 2 more rows\n"))
+}
+
+func TestCollectionTableBox(t *testing.T) {
+	var buffer bytes.Buffer
+	assert.Nil(t, collection.New("apple", "strawberry").Table(&buffer, collection.TableOptions{
+		Style:    collection.TableBox,
+		MaxWidth: 8,
+	}))
+
+	expected := "" +
+		"┌──────────┐\n" +
+		"│ Value    │\n" +
+		"├──────────┤\n" +
+		"│ apple    │\n" +
+		"│ strawbe… │\n" +
+		"└──────────┘\n"
+	assert.Equal(t, expected, buffer.String())
+}
+
+func TestCollectionTableErrors(t *testing.T) {
+	var buffer bytes.Buffer
+
+	assert.NotNil(t, returnFruits().Table(&buffer, collection.TableOptions{Columns: []string{"Weight"}}))
+	assert.NotNil(t, returnFruits().Table(&buffer, collection.TableOptions{Style: 42}))
+}
@@ -0,0 +1,219 @@
+package collection
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Frequencies returns the number of times each distinct item occurs in the
+// specified collection, ordered from the most to the least common. Items which
+// occur equally often keep the order in which they first appear.
+func Frequencies[T comparable](c *Collection[T]) *OrderedMap[T, int] {
+	var (
+		counts = make(map[T]int)
+		order  []T
+	)
+
+	for _, item := range c.snapshot() {
+		if counts[item] == 0 {
+			order = append(order, item)
+		}
+		counts[item]++
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return counts[order[i]] > counts[order[j]]
+	})
+
+	out := NewOrderedMap[T, int]()
+	for _, item := range order {
+		out.Set(item, counts[item])
+	}
+
+	return out
+}
+
+// Mode returns the most common item of the specified collection, preferring
+// the one which appears first when several are equally common, along with a
+// boolean value stating whether or not the collection had any items.
+func Mode[T comparable](c *Collection[T]) (out T, found bool) {
+	entries := MostCommon(c, 1)
+	if entries.IsEmpty() {
+		return out, false
+	}
+
+	entry, _ := entries.AtFirst()
+	return entry.Key, true
+}
+
+// MostCommon returns up to `n` of the most common items of the specified
+// collection along with their counts, in the order used by Frequencies.
+// ( Chainable )
+func MostCommon[T comparable](c *Collection[T], n int) *Collection[Entry[T, int]] {
+	entries := Frequencies(c).Entries()
+	if n < 0 {
+		n = 0
+	}
+
+	return entries.Slice(0, n)
+}
+
+// BinStrategy determines how Histogram divides values into bins.
+type BinStrategy int
+
+const (
+	// BinFixedWidth divides the range between the smallest and largest values
+	// into bins of equal width.
+	BinFixedWidth BinStrategy = iota
+	// BinQuantile places bin boundaries at quantiles, so that every bin holds
+	// roughly the same number of values.
+	BinQuantile
+)
+
+// Bin is a single bin of a histogram, counting the values between Lower and
+// Upper. Bins include their lower bound, and the last bin of a histogram also
+// includes its upper bound.
+type Bin struct {
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+	Count int     `json:"count"`
+}
+
+// Bins is a histogram produced by Histogram.
+type Bins []Bin
+
+// Histogram divides the numbers returned by the specified function for each
+// item of the specified collection into up to `bins` bins, as determined by
+// the specified strategy. NaN values are ignored.
+func Histogram[T any](c *Collection[T], bins int, strategy BinStrategy, value func(T) float64) Bins {
+	items := c.snapshot()
+
+	values := make([]float64, 0, len(items))
+	for _, item := range items {
+		if v := value(item); !math.IsNaN(v) {
+			values = append(values, v)
+		}
+	}
+
+	if len(values) == 0 || bins < 1 {
+		return Bins{}
+	}
+
+	sort.Float64s(values)
+
+	if strategy == BinQuantile {
+		return quantileBins(values, bins)
+	}
+
+	return fixedWidthBins(values, bins)
+}
+
+func fixedWidthBins(values []float64, bins int) Bins {
+	lower, upper := values[0], values[len(values)-1]
+	if lower == upper {
+		return Bins{{lower, upper, len(values)}}
+	}
+
+	width := (upper - lower) / float64(bins)
+
+	out := make(Bins, bins)
+	for i := range out {
+		out[i].Lower = lower + float64(i)*width
+		out[i].Upper = lower + float64(i+1)*width
+	}
+	out[bins-1].Upper = upper
+
+	for _, v := range values {
+		i := int((v - lower) / width)
+		if i >= bins {
+			i = bins - 1
+		}
+		out[i].Count++
+	}
+
+	return out
+}
+
+func quantileBins(values []float64, bins int) Bins {
+	var out Bins
+
+	for i, from := 0, 0; i < bins && from < len(values); i++ {
+		to := (i + 1) * len(values) / bins
+		if to <= from {
+			continue
+		}
+
+		// Equal values must share a bin, so boundaries are moved past them.
+		for to < len(values) && values[to] == values[to-1] {
+			to++
+		}
+
+		bin := Bin{Lower: values[from], Upper: values[len(values)-1], Count: to - from}
+		if to < len(values) {
+			bin.Upper = values[to]
+		}
+
+		out = append(out, bin)
+		from = to
+	}
+
+	return out
+}
+
+// Render writes the current histogram to the specified writer as a text bar
+// chart, with the longest bar being `width` characters wide.
+func (b Bins) Render(w io.Writer, width int) error {
+	entries := New[Entry[string, int]]()
+	for i, bin := range b {
+		closing := ")"
+		if i == len(b)-1 {
+			closing = "]"
+		}
+
+		entries.Push(Entry[string, int]{fmt.Sprintf("[%g, %g%s", bin.Lower, bin.Upper, closing), bin.Count})
+	}
+
+	return BarChart(w, entries, width)
+}
+
+// BarChart writes the specified entries, such as those returned by MostCommon,
+// to the specified writer as a text bar chart, with the longest bar being
+// `width` characters wide.
+func BarChart[K comparable](w io.Writer, entries *Collection[Entry[K, int]], width int) error {
+	items := entries.snapshot()
+
+	var (
+		labels   = make([]string, len(items))
+		longest  int
+		greatest int
+	)
+
+	for i, entry := range items {
+		labels[i] = fmt.Sprint(entry.Key)
+		if n := utf8.RuneCountInString(labels[i]); n > longest {
+			longest = n
+		}
+
+		if entry.Value > greatest {
+			greatest = entry.Value
+		}
+	}
+
+	buffer := bufio.NewWriter(w)
+	for i, entry := range items {
+		bar := 0
+		if greatest > 0 && entry.Value > 0 {
+			bar = int(math.Round(float64(entry.Value) / float64(greatest) * float64(width)))
+		}
+
+		padding := strings.Repeat(" ", longest-utf8.RuneCountInString(labels[i]))
+		fmt.Fprintf(buffer, "%s%s | %s %d\n", labels[i], padding, strings.Repeat("#", bar), entry.Value)
+	}
+
+	return buffer.Flush()
+}
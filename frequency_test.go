@@ -0,0 +1,75 @@
+package collection_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wilhelm-murdoch/go-collection"
+)
+
+func TestFrequencies(t *testing.T) {
+	c := collection.New("b", "a", "c", "a", "b", "a", "d")
+
+	frequencies := collection.Frequencies(c)
+	assert.Equal(t, []string{"a", "b", "c", "d"}, frequencies.Keys().Items(), "Ties should keep the order of first appearance.")
+	assert.Equal(t, []int{3, 2, 1, 1}, frequencies.Values().Items())
+
+	mode, found := collection.Mode(c)
+	assert.True(t, found)
+	assert.Equal(t, "a", mode)
+
+	_, found = collection.Mode(collection.New[string]())
+	assert.False(t, found)
+
+	assert.Equal(t, []collection.Entry[string, int]{{"a", 3}, {"b", 2}}, collection.MostCommon(c, 2).Items())
+	assert.Equal(t, 4, collection.MostCommon(c, 10).Length())
+	assert.Equal(t, 0, collection.MostCommon(c, -1).Length())
+}
+
+func TestHistogramFixedWidth(t *testing.T) {
+	c := collection.New(0.0, 1, 2, 2.5, 5, 7.5, 9, 10)
+	identity := func(f float64) float64 { return f }
+
+	assert.Equal(t, collection.Bins{
+		{Lower: 0, Upper: 2.5, Count: 3},
+		{Lower: 2.5, Upper: 5, Count: 1},
+		{Lower: 5, Upper: 7.5, Count: 1},
+		{Lower: 7.5, Upper: 10, Count: 3},
+	}, collection.Histogram(c, 4, collection.BinFixedWidth, identity))
+
+	assert.Equal(t, collection.Bins{{Lower: 3, Upper: 3, Count: 2}},
+		collection.Histogram(collection.New(3.0, 3), 4, collection.BinFixedWidth, identity))
+	assert.Empty(t, collection.Histogram(collection.New[float64](), 4, collection.BinFixedWidth, identity))
+}
+
+func TestHistogramQuantile(t *testing.T) {
+	c := collection.New(1, 1, 1, 1, 2, 3, 4, 5, 100, 1000)
+
+	bins := collection.Histogram(c, 3, collection.BinQuantile, func(i int) float64 { return float64(i) })
+	assert.Equal(t, collection.Bins{
+		{Lower: 1, Upper: 2, Count: 4},
+		{Lower: 2, Upper: 4, Count: 2},
+		{Lower: 4, Upper: 1000, Count: 4},
+	}, bins, "Equal values should never be split across bins.")
+}
+
+func TestBarChart(t *testing.T) {
+	var buffer bytes.Buffer
+	assert.Nil(t, collection.BarChart(&buffer, collection.MostCommon(collection.New("apple", "fig", "apple", "apple", "kiwi", "fig"), 3), 6))
+
+	expected := "" +
+		"apple | ###### 3\n" +
+		"fig   | #### 2\n" +
+		"kiwi  | ## 1\n"
+	assert.Equal(t, expected, buffer.String())
+
+	buffer.Reset()
+	bins := collection.Histogram(collection.New(1.0, 2, 2, 4), 2, collection.BinFixedWidth, func(f float64) float64 { return f })
+	assert.Nil(t, bins.Render(&buffer, 4))
+
+	expected = "" +
+		"[1, 2.5) | #### 3\n" +
+		"[2.5, 4] | # 1\n"
+	assert.Equal(t, expected, buffer.String())
+}
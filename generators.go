@@ -0,0 +1,119 @@
+package collection
+
+import "strings"
+
+// Number is satisfied by the integer and floating point types, along with any
+// type derived from them.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Range returns a new collection of numbers starting at `start` and moving
+// towards, but never reaching, `end` in increments of `step`. A negative step
+// counts down. Range panics if step is zero. ( Chainable )
+func Range[T Number](start, end, step T) *Collection[T] {
+	if step == 0 {
+		panic("collection: range step must not be zero")
+	}
+
+	out := New[T]()
+
+	// Values are computed from the start rather than accumulated, so floating
+	// point steps do not drift.
+	for i := 0; ; i++ {
+		value := start + T(i)*step
+		if (step > 0 && value >= end) || (step < 0 && value <= end) {
+			break
+		}
+
+		// Stop rather than wrap around when the type overflows.
+		if last, ok := out.AtLast(); ok && (step > 0) != (value > last) {
+			break
+		}
+
+		out.Push(value)
+	}
+
+	return out
+}
+
+// Repeat returns a new collection holding the specified item `n` times.
+// ( Chainable )
+func Repeat[T any](item T, n int) *Collection[T] {
+	return Generate(n, func(int) T { return item })
+}
+
+// Generate returns a new collection of `n` items, each returned by the
+// specified function given its index. ( Chainable )
+func Generate[T any](n int, f func(i int) T) *Collection[T] {
+	if n < 0 {
+		n = 0
+	}
+
+	items := make([]T, n)
+	for i := range items {
+		items[i] = f(i)
+	}
+
+	return New(items...)
+}
+
+// Unfold returns a new collection built from the specified seed. The specified
+// function is called with the current state and returns the next item, the
+// next state and a boolean value stating whether or not to continue; the item
+// is discarded once it returns false. ( Chainable )
+func Unfold[T, S any](seed S, f func(S) (T, S, bool)) *Collection[T] {
+	out := New[T]()
+	for state := seed; ; {
+		item, next, ok := f(state)
+		if !ok {
+			return out
+		}
+
+		out.Push(item)
+		state = next
+	}
+}
+
+// FromMap returns a new collection holding the result of the specified
+// function for every key and value of the specified map, in no particular
+// order. ( Chainable )
+func FromMap[K comparable, V, T any](m map[K]V, f func(K, V) T) *Collection[T] {
+	out := New[T]()
+	for k, v := range m {
+		out.Push(f(k, v))
+	}
+
+	return out
+}
+
+// FromKeys returns a new collection holding the keys of the specified map, in
+// no particular order. ( Chainable )
+func FromKeys[K comparable, V any](m map[K]V) *Collection[K] {
+	return FromMap(m, func(k K, _ V) K { return k })
+}
+
+// FromValues returns a new collection holding the values of the specified
+// map, in no particular order. ( Chainable )
+func FromValues[K comparable, V any](m map[K]V) *Collection[V] {
+	return FromMap(m, func(_ K, v V) V { return v })
+}
+
+// FromString returns a new collection holding the substrings of `s` between
+// each instance of `sep`, or each UTF-8 character of `s` if `sep` is empty.
+// An empty string produces an empty collection. ( Chainable )
+func FromString(s, sep string) *Collection[string] {
+	if s == "" {
+		return New[string]()
+	}
+
+	return New(strings.Split(s, sep)...)
+}
+
+// Runes returns a new collection holding the runes of the specified string.
+// ( Chainable )
+func Runes(s string) *Collection[rune] {
+	return New([]rune(s)...)
+}
@@ -0,0 +1,57 @@
+package collection_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wilhelm-murdoch/go-collection"
+)
+
+func TestRange(t *testing.T) {
+	assert.Equal(t, []int{0, 1, 2, 3, 4}, collection.Range(0, 5, 1).Items())
+	assert.Equal(t, []int{10, 7, 4, 1}, collection.Range(10, 0, -3).Items())
+	assert.Equal(t, []uint8{2, 4}, collection.Range[uint8](2, 6, 2).Items())
+	assert.Equal(t, []uint8{250}, collection.Range[uint8](250, 255, 10).Items(), "Ranges should stop rather than overflow.")
+	assert.Equal(t, []float64{0, 0.1, 0.2, 0.30000000000000004, 0.4}, collection.Range(0, 0.5, 0.1).Items())
+	assert.Equal(t, 10, collection.Range(0, 1, 0.1).Length(), "Float steps should not drift past the end.")
+	assert.True(t, collection.Range(5, 0, 1).IsEmpty())
+	assert.Panics(t, func() { collection.Range(0, 5, 0) })
+}
+
+func TestRepeatGenerate(t *testing.T) {
+	assert.Equal(t, []string{"a", "a", "a"}, collection.Repeat("a", 3).Items())
+	assert.True(t, collection.Repeat("a", -1).IsEmpty())
+	assert.Equal(t, []int{0, 1, 4, 9}, collection.Generate(4, func(i int) int { return i * i }).Items())
+}
+
+func TestUnfold(t *testing.T) {
+	fibonacci := collection.Unfold([2]int{0, 1}, func(s [2]int) (int, [2]int, bool) {
+		return s[0], [2]int{s[1], s[0] + s[1]}, s[0] < 50
+	})
+
+	assert.Equal(t, []int{0, 1, 1, 2, 3, 5, 8, 13, 21, 34}, fibonacci.Items())
+}
+
+func TestFromMap(t *testing.T) {
+	m := map[string]int{"a": 1, "b": 2, "c": 3}
+
+	pairs := collection.FromMap(m, func(k string, v int) Server { return Server{k, v} })
+	pairs.Sort(func(i, j int) bool { return pairs.Items()[i].Host < pairs.Items()[j].Host })
+	assert.Equal(t, []Server{{"a", 1}, {"b", 2}, {"c", 3}}, pairs.Items())
+
+	keys := collection.FromKeys(m).Items()
+	sort.Strings(keys)
+	assert.Equal(t, []string{"a", "b", "c"}, keys)
+
+	values := collection.FromValues(m).Items()
+	sort.Ints(values)
+	assert.Equal(t, []int{1, 2, 3}, values)
+}
+
+func TestFromString(t *testing.T) {
+	assert.Equal(t, []string{"a", "b", "c"}, collection.FromString("a,b,c", ",").Items())
+	assert.Equal(t, []string{"h", "é", "!"}, collection.FromString("hé!", "").Items())
+	assert.True(t, collection.FromString("", ",").IsEmpty())
+	assert.Equal(t, []rune{'h', 'é', '!'}, collection.Runes("hé!").Items())
+}
@@ -0,0 +1,289 @@
+package collection
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrDuplicateKey is returned when an item would add a second entry for the
+// same key to a unique index.
+var ErrDuplicateKey = errors.New("collection: duplicate key in unique index")
+
+// Indexed wraps a collection of items of type T with named secondary indexes
+// which are kept in sync as items are added and removed, allowing items to be
+// looked up by key in O(1) rather than with a linear scan. Index keys must be
+// comparable and items must not be modified in ways that change their keys
+// while they are held by the collection.
+type Indexed[T any] struct {
+	items   *Collection[T]
+	indexes map[string]*secondaryIndex[T]
+	base    int
+}
+
+// secondaryIndex maps keys to stored positions. A stored position `s` refers to
+// the item found at index `s - base` of the wrapped collection, which allows
+// items to be shifted off the front without renumbering every entry.
+type secondaryIndex[T any] struct {
+	key     func(T) any
+	unique  bool
+	entries map[any][]int
+}
+
+// NewIndexed returns a new indexed collection containing the specified items.
+// ( Chainable )
+func NewIndexed[T any](items ...T) *Indexed[T] {
+	return &Indexed[T]{
+		items:   New(append([]T(nil), items...)...),
+		indexes: make(map[string]*secondaryIndex[T]),
+	}
+}
+
+// AddIndex registers a multi-valued index with the specified name, keyed by the
+// result of the specified key function, replacing any index of the same name.
+// ( Chainable )
+func (x *Indexed[T]) AddIndex(name string, key func(T) any) *Indexed[T] {
+	x.addIndex(name, key, false)
+	return x
+}
+
+// AddUniqueIndex registers a unique index with the specified name, keyed by the
+// result of the specified key function, replacing any index of the same name.
+// An error wrapping ErrDuplicateKey is returned, and the index is not added, if
+// existing items share a key.
+func (x *Indexed[T]) AddUniqueIndex(name string, key func(T) any) error {
+	return x.addIndex(name, key, true)
+}
+
+func (x *Indexed[T]) addIndex(name string, key func(T) any, unique bool) error {
+	index := &secondaryIndex[T]{
+		key:     key,
+		unique:  unique,
+		entries: make(map[any][]int),
+	}
+
+	for i, item := range x.items.items {
+		k := key(item)
+		if unique && len(index.entries[k]) > 0 {
+			return fmt.Errorf("%w: index %q, key %v", ErrDuplicateKey, name, k)
+		}
+		index.entries[k] = append(index.entries[k], i+x.base)
+	}
+
+	x.indexes[name] = index
+	return nil
+}
+
+// RemoveIndex unregisters the index with the specified name. ( Chainable )
+func (x *Indexed[T]) RemoveIndex(name string) *Indexed[T] {
+	delete(x.indexes, name)
+	return x
+}
+
+// Lookup returns a new collection of items matching the specified key in the
+// specified index along with their positions, in ascending order. Both are
+// empty if the index or key could not be found.
+func (x *Indexed[T]) Lookup(name string, key any) (*Collection[T], []int) {
+	index, found := x.indexes[name]
+	if !found {
+		return New[T](), nil
+	}
+
+	positions := make([]int, len(index.entries[key]))
+	for i, stored := range index.entries[key] {
+		positions[i] = stored - x.base
+	}
+	sort.Ints(positions)
+
+	out := make([]T, len(positions))
+	for i, position := range positions {
+		out[i] = x.items.items[position]
+	}
+
+	return New(out...), positions
+}
+
+// LookupFirst returns the first item matching the specified key in the
+// specified index along with a boolean value stating whether or not an item
+// could be found.
+func (x *Indexed[T]) LookupFirst(name string, key any) (out T, found bool) {
+	items, _ := x.Lookup(name, key)
+	return items.AtFirst()
+}
+
+// Collection returns a new collection containing a copy of the current
+// collection's items. ( Chainable )
+func (x *Indexed[T]) Collection() *Collection[T] {
+	return New(append([]T(nil), x.items.items...)...)
+}
+
+// Items returns the current collection's set of items.
+func (x *Indexed[T]) Items() []T {
+	return x.items.Items()
+}
+
+// Length returns number of items associated with the current collection.
+func (x *Indexed[T]) Length() int {
+	return x.items.Length()
+}
+
+// At attempts to return the item associated with the specified index for the
+// current collection along with a boolean value stating whether or not an item
+// could be found.
+func (x *Indexed[T]) At(index int) (T, bool) {
+	return x.items.At(index)
+}
+
+// Push method appends one or more items to the end of the current collection,
+// returning the new length. If any item would violate a unique index, none of
+// the items are added and an error wrapping ErrDuplicateKey is returned.
+func (x *Indexed[T]) Push(items ...T) (int, error) {
+	if err := x.checkUnique(items...); err != nil {
+		return x.Length(), err
+	}
+
+	for _, item := range items {
+		x.index(item, x.Length()+x.base)
+		x.items.Push(item)
+	}
+
+	return x.Length(), nil
+}
+
+// InsertAt inserts the specified item at the specified index following the same
+// rules as Collection.InsertAt. If the item would violate a unique index, it is
+// not added and an error wrapping ErrDuplicateKey is returned.
+func (x *Indexed[T]) InsertAt(item T, index int) error {
+	if err := x.checkUnique(item); err != nil {
+		return err
+	}
+
+	if index < 0 {
+		index = 0
+	}
+
+	if index > x.Length() {
+		index = x.Length()
+	}
+
+	x.renumber(index, 1)
+	x.index(item, index+x.base)
+	x.items.InsertAt(item, index)
+
+	return nil
+}
+
+// Pop method removes the last item from the current collection and then
+// returns that item along with a boolean value stating whether or not an item
+// could be found.
+func (x *Indexed[T]) Pop() (T, bool) {
+	return x.RemoveAt(x.Length() - 1)
+}
+
+// Shift method removes the first item from the current collection and then
+// returns that item along with a boolean value stating whether or not an item
+// could be found.
+func (x *Indexed[T]) Shift() (out T, found bool) {
+	if out, found = x.items.AtFirst(); !found {
+		return
+	}
+
+	x.unindex(out, x.base)
+	x.items.Shift()
+	x.base++
+
+	return out, true
+}
+
+// RemoveAt removes the item at the specified index from the current collection
+// and then returns that item along with a boolean value stating whether or not
+// an item could be found.
+func (x *Indexed[T]) RemoveAt(index int) (out T, found bool) {
+	if index == 0 {
+		return x.Shift()
+	}
+
+	if out, found = x.items.At(index); !found {
+		return
+	}
+
+	x.unindex(out, index+x.base)
+	x.renumber(index+1, -1)
+	x.items.items = append(x.items.items[:index], x.items.items[index+1:]...)
+
+	return out, true
+}
+
+// Empty will reset the current collection and all of its indexes to zero
+// items. ( Chainable )
+func (x *Indexed[T]) Empty() *Indexed[T] {
+	x.items.Empty()
+	x.base = 0
+	for _, index := range x.indexes {
+		index.entries = make(map[any][]int)
+	}
+
+	return x
+}
+
+// checkUnique ensures the specified items neither collide with existing items
+// nor with each other in any unique index.
+func (x *Indexed[T]) checkUnique(items ...T) error {
+	for name, index := range x.indexes {
+		if !index.unique {
+			continue
+		}
+
+		seen := make(map[any]bool, len(items))
+		for _, item := range items {
+			k := index.key(item)
+			if seen[k] || len(index.entries[k]) > 0 {
+				return fmt.Errorf("%w: index %q, key %v", ErrDuplicateKey, name, k)
+			}
+			seen[k] = true
+		}
+	}
+
+	return nil
+}
+
+func (x *Indexed[T]) index(item T, stored int) {
+	for _, index := range x.indexes {
+		k := index.key(item)
+		index.entries[k] = append(index.entries[k], stored)
+	}
+}
+
+func (x *Indexed[T]) unindex(item T, stored int) {
+	for _, index := range x.indexes {
+		k := index.key(item)
+
+		positions := index.entries[k]
+		for i, p := range positions {
+			if p == stored {
+				positions = append(positions[:i], positions[i+1:]...)
+				break
+			}
+		}
+
+		if len(positions) == 0 {
+			delete(index.entries, k)
+		} else {
+			index.entries[k] = positions
+		}
+	}
+}
+
+// renumber shifts every stored position at or after the specified collection
+// index by delta.
+func (x *Indexed[T]) renumber(from, delta int) {
+	for _, index := range x.indexes {
+		for _, positions := range index.entries {
+			for i, p := range positions {
+				if p >= from+x.base {
+					positions[i] = p + delta
+				}
+			}
+		}
+	}
+}
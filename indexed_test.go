@@ -0,0 +1,103 @@
+package collection_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wilhelm-murdoch/go-collection"
+)
+
+type Account struct {
+	Email string
+	Team  string
+}
+
+func returnIndexed(t *testing.T) *collection.Indexed[Account] {
+	x := collection.NewIndexed(
+		Account{"luke@example.com", "red"},
+		Account{"rob@example.com", "blue"},
+		Account{"peter@example.com", "red"},
+	)
+
+	assert.Nil(t, x.AddUniqueIndex("email", func(a Account) any { return a.Email }))
+	x.AddIndex("team", func(a Account) any { return a.Team })
+
+	return x
+}
+
+func TestIndexedLookup(t *testing.T) {
+	x := returnIndexed(t)
+
+	items, positions := x.Lookup("team", "red")
+	assert.Equal(t, []int{0, 2}, positions)
+	assert.Equal(t, "peter@example.com", items.Items()[1].Email)
+
+	account, found := x.LookupFirst("email", "rob@example.com")
+	assert.True(t, found)
+	assert.Equal(t, "blue", account.Team)
+
+	_, found = x.LookupFirst("email", "josh@example.com")
+	assert.False(t, found)
+
+	items, positions = x.Lookup("missing", "red")
+	assert.True(t, items.IsEmpty(), "Unknown indexes should return no items.")
+	assert.Nil(t, positions)
+}
+
+func TestIndexedUnique(t *testing.T) {
+	x := returnIndexed(t)
+
+	_, err := x.Push(Account{"josh@example.com", "blue"}, Account{"rob@example.com", "red"})
+	assert.True(t, errors.Is(err, collection.ErrDuplicateKey))
+	assert.Equal(t, 3, x.Length(), "No items should be added when one violates a unique index.")
+
+	err = x.InsertAt(Account{"luke@example.com", "blue"}, 1)
+	assert.True(t, errors.Is(err, collection.ErrDuplicateKey))
+
+	err = x.AddUniqueIndex("team", func(a Account) any { return a.Team })
+	assert.True(t, errors.Is(err, collection.ErrDuplicateKey), "Existing duplicates should prevent a unique index.")
+
+	_, positions := x.Lookup("team", "red")
+	assert.Equal(t, []int{0, 2}, positions, "A rejected unique index should not replace the existing one.")
+}
+
+func TestIndexedMutations(t *testing.T) {
+	x := returnIndexed(t)
+
+	length, err := x.Push(Account{"josh@example.com", "red"})
+	assert.Nil(t, err)
+	assert.Equal(t, 4, length)
+
+	assert.Nil(t, x.InsertAt(Account{"wilhelm@example.com", "blue"}, 1))
+	_, positions := x.Lookup("team", "red")
+	assert.Equal(t, []int{0, 3, 4}, positions)
+
+	shifted, _ := x.Shift()
+	assert.Equal(t, "luke@example.com", shifted.Email)
+	_, positions = x.Lookup("team", "red")
+	assert.Equal(t, []int{2, 3}, positions, "Positions should follow items shifted off the front.")
+
+	popped, _ := x.Pop()
+	assert.Equal(t, "josh@example.com", popped.Email)
+	_, found := x.LookupFirst("email", "josh@example.com")
+	assert.False(t, found, "Popped items should be removed from every index.")
+
+	removed, _ := x.RemoveAt(1)
+	assert.Equal(t, "rob@example.com", removed.Email)
+	_, positions = x.Lookup("team", "blue")
+	assert.Equal(t, []int{0}, positions)
+
+	account, _ := x.LookupFirst("email", "peter@example.com")
+	assert.Equal(t, "red", account.Team)
+	_, positions = x.Lookup("email", "peter@example.com")
+	assert.Equal(t, []int{1}, positions)
+
+	x.Empty()
+	assert.Equal(t, 0, x.Length())
+	_, positions = x.Lookup("team", "blue")
+	assert.Empty(t, positions)
+
+	_, found = x.Shift()
+	assert.False(t, found, "Shifting an empty collection should not find an item.")
+}
@@ -0,0 +1,261 @@
+package collection
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// ErrJSONPatchTestFailed is returned when a `test` operation of a JSON Patch
+// does not match the collection.
+var ErrJSONPatchTestFailed = errors.New("collection: json patch test failed")
+
+// JSONPatchOperation is a single operation of an RFC 6902 JSON Patch.
+type JSONPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// JSONPatch is an RFC 6902 JSON Patch document targeting the items of a
+// collection, as marshalled by MarshalJSON. Only top-level array indices, and
+// `-` to append, are supported as paths.
+type JSONPatch []JSONPatchOperation
+
+// ParseJSONPatch parses and validates a JSON Patch document.
+func ParseJSONPatch(data []byte) (JSONPatch, error) {
+	var out JSONPatch
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("collection: invalid json patch: %w", err)
+	}
+
+	for i, op := range out {
+		if err := op.validate(); err != nil {
+			return nil, fmt.Errorf("collection: json patch operation %d: %w", i, err)
+		}
+	}
+
+	return out, nil
+}
+
+func (op JSONPatchOperation) validate() error {
+	switch op.Op {
+	case "add", "replace", "test":
+		if len(op.Value) == 0 {
+			return fmt.Errorf("%s requires a value", op.Op)
+		}
+	case "move", "copy":
+		if _, _, err := parseJSONPointer(op.From); err != nil {
+			return fmt.Errorf("from: %w", err)
+		}
+	case "remove":
+	default:
+		return fmt.Errorf("unknown op %q", op.Op)
+	}
+
+	_, _, err := parseJSONPointer(op.Path)
+	return err
+}
+
+// parseJSONPointer parses a JSON Pointer referencing a top-level array index,
+// returning the index or, for `/-`, a true boolean value.
+func parseJSONPointer(pointer string) (int, bool, error) {
+	if len(pointer) < 2 || pointer[0] != '/' {
+		return 0, false, fmt.Errorf("invalid path %q", pointer)
+	}
+
+	token := pointer[1:]
+	if token == "-" {
+		return 0, true, nil
+	}
+
+	if (len(token) > 1 && token[0] == '0') || token[0] == '+' {
+		return 0, false, fmt.Errorf("invalid index in path %q", pointer)
+	}
+
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 {
+		return 0, false, fmt.Errorf("only top-level indices are supported, not %q", pointer)
+	}
+
+	return index, false, nil
+}
+
+// ApplyJSONPatch applies the specified JSON Patch to the current collection.
+// Operations are applied in order and atomically: if any operation fails,
+// including a `test`, or the patched items break the collection's validation
+// rules, an error is returned and the collection is left untouched.
+func (c *Collection[T]) ApplyJSONPatch(p JSONPatch) error {
+	defer c.lock()()
+
+	items := append([]T(nil), c.items...)
+
+	for i, op := range p {
+		var err error
+		if items, err = applyJSONPatchOperation(items, op); err != nil {
+			return fmt.Errorf("collection: json patch operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+
+	return c.replace(items)
+}
+
+func applyJSONPatchOperation[T any](items []T, op JSONPatchOperation) ([]T, error) {
+	if err := op.validate(); err != nil {
+		return nil, err
+	}
+
+	index, end, _ := parseJSONPointer(op.Path)
+
+	// Every operation other than `add` must target an existing item.
+	if op.Op != "add" && op.Op != "move" && op.Op != "copy" && (end || index >= len(items)) {
+		return nil, fmt.Errorf("path %q does not exist", op.Path)
+	}
+
+	switch op.Op {
+	case "add":
+		var item T
+		if err := json.Unmarshal(op.Value, &item); err != nil {
+			return nil, err
+		}
+		return insertJSONPatchItem(items, op.Path, item)
+	case "remove":
+		return append(items[:index], items[index+1:]...), nil
+	case "replace":
+		var item T
+		if err := json.Unmarshal(op.Value, &item); err != nil {
+			return nil, err
+		}
+		items[index] = item
+		return items, nil
+	case "test":
+		equal, err := jsonEqual(items[index], op.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		if !equal {
+			return nil, ErrJSONPatchTestFailed
+		}
+		return items, nil
+	}
+
+	from, fromEnd, _ := parseJSONPointer(op.From)
+	if fromEnd || from >= len(items) {
+		return nil, fmt.Errorf("from %q does not exist", op.From)
+	}
+
+	item := items[from]
+	if op.Op == "move" {
+		items = append(items[:from], items[from+1:]...)
+	}
+
+	return insertJSONPatchItem(items, op.Path, item)
+}
+
+func insertJSONPatchItem[T any](items []T, path string, item T) ([]T, error) {
+	index, end, _ := parseJSONPointer(path)
+	if end {
+		index = len(items)
+	}
+
+	if index > len(items) {
+		return nil, fmt.Errorf("index %d is out of bounds", index)
+	}
+
+	var zero T
+	items = append(items, zero)
+	copy(items[index+1:], items[index:])
+	items[index] = item
+
+	return items, nil
+}
+
+// jsonEqual reports whether the JSON encoding of item is semantically equal to
+// the specified JSON value.
+func jsonEqual(item any, value json.RawMessage) (bool, error) {
+	encoded, err := json.Marshal(item)
+	if err != nil {
+		return false, err
+	}
+
+	var left, right any
+	decoder := json.NewDecoder(bytes.NewReader(value))
+	decoder.UseNumber()
+	if err := decoder.Decode(&right); err != nil {
+		return false, err
+	}
+
+	decoder = json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	if err := decoder.Decode(&left); err != nil {
+		return false, err
+	}
+
+	return reflect.DeepEqual(normaliseJSON(left), normaliseJSON(right)), nil
+}
+
+// normaliseJSON converts json.Number values to float64 so that numbers written
+// differently, such as `1` and `1.0`, compare as equal.
+func normaliseJSON(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return v.String()
+		}
+		return f
+	case []any:
+		for i := range v {
+			v[i] = normaliseJSON(v[i])
+		}
+	case map[string]any:
+		for k := range v {
+			v[k] = normaliseJSON(v[k])
+		}
+	}
+
+	return value
+}
+
+// CreateJSONPatch returns a JSON Patch which turns the `old` collection into
+// the `new` one, made up of `add` and `remove` operations derived from Diff.
+// Items are compared using `reflect.DeepEqual`.
+func CreateJSONPatch[T any](old, new *Collection[T]) (JSONPatch, error) {
+	old, new = New(old.snapshot()...), New(new.snapshot()...)
+
+	var (
+		out           JSONPatch
+		index, length = 0, old.Length()
+	)
+
+	for _, edit := range Diff(old, new, func(a, b T) bool { return reflect.DeepEqual(a, b) }) {
+		path := "/" + strconv.Itoa(index)
+
+		switch edit.Op {
+		case EditEqual:
+			index++
+		case EditDelete:
+			out = append(out, JSONPatchOperation{Op: "remove", Path: path})
+			length--
+		case EditInsert:
+			value, err := json.Marshal(edit.Item)
+			if err != nil {
+				return nil, err
+			}
+
+			if index == length {
+				path = "/-"
+			}
+
+			out = append(out, JSONPatchOperation{Op: "add", Path: path, Value: value})
+			index, length = index+1, length+1
+		}
+	}
+
+	return out, nil
+}
@@ -0,0 +1,102 @@
+package collection_test
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wilhelm-murdoch/go-collection"
+)
+
+func TestCollectionApplyJSONPatch(t *testing.T) {
+	c := collection.New(Server{"a", 1}, Server{"b", 2}, Server{"c", 3})
+
+	patch, err := collection.ParseJSONPatch([]byte(`[
+		{"op": "test", "path": "/1", "value": {"port": 2.0, "host": "b"}},
+		{"op": "replace", "path": "/1", "value": {"host": "B", "port": 20}},
+		{"op": "add", "path": "/-", "value": {"host": "d", "port": 4}},
+		{"op": "add", "path": "/0", "value": {"host": "z", "port": 0}},
+		{"op": "remove", "path": "/3"},
+		{"op": "move", "from": "/0", "path": "/3"},
+		{"op": "copy", "from": "/1", "path": "/0"}
+	]`))
+	assert.Nil(t, err)
+
+	assert.Nil(t, c.ApplyJSONPatch(patch))
+	assert.Equal(t, []Server{{"B", 20}, {"a", 1}, {"B", 20}, {"d", 4}, {"z", 0}}, c.Items())
+}
+
+func TestCollectionApplyJSONPatchAtomic(t *testing.T) {
+	c := collection.New(1, 2, 3)
+
+	patch, err := collection.ParseJSONPatch([]byte(`[
+		{"op": "remove", "path": "/0"},
+		{"op": "test", "path": "/0", "value": 3}
+	]`))
+	assert.Nil(t, err)
+
+	err = c.ApplyJSONPatch(patch)
+	assert.True(t, errors.Is(err, collection.ErrJSONPatchTestFailed))
+	assert.Equal(t, []int{1, 2, 3}, c.Items(), "A failed patch should leave the collection untouched.")
+
+	failures := []string{
+		`[{"op": "remove", "path": "/3"}]`,
+		`[{"op": "replace", "path": "/-", "value": 1}]`,
+		`[{"op": "add", "path": "/9", "value": 1}]`,
+		`[{"op": "add", "path": "/0", "value": "one"}]`,
+		`[{"op": "move", "from": "/5", "path": "/0"}]`,
+	}
+
+	for _, document := range failures {
+		patch, err := collection.ParseJSONPatch([]byte(document))
+		assert.Nil(t, err, document)
+		assert.NotNil(t, c.ApplyJSONPatch(patch), document)
+		assert.Equal(t, []int{1, 2, 3}, c.Items(), document)
+	}
+}
+
+func TestParseJSONPatchErrors(t *testing.T) {
+	invalid := []string{
+		`{"op": "add"}`,
+		`[{"op": "frobnicate", "path": "/0"}]`,
+		`[{"op": "add", "path": "/0"}]`,
+		`[{"op": "remove", "path": "0"}]`,
+		`[{"op": "remove", "path": "/01"}]`,
+		`[{"op": "remove", "path": "/0/name"}]`,
+		`[{"op": "copy", "path": "/0"}]`,
+	}
+
+	for _, document := range invalid {
+		_, err := collection.ParseJSONPatch([]byte(document))
+		assert.NotNil(t, err, document)
+	}
+}
+
+func TestCreateJSONPatch(t *testing.T) {
+	old := collection.New("a", "b", "c")
+	new := collection.New("b", "c", "d", "e")
+
+	patch, err := collection.CreateJSONPatch(old, new)
+	assert.Nil(t, err)
+	assert.Equal(t, collection.JSONPatch{
+		{Op: "remove", Path: "/0"},
+		{Op: "add", Path: "/-", Value: []byte(`"d"`)},
+		{Op: "add", Path: "/-", Value: []byte(`"e"`)},
+	}, patch)
+
+	for i := 0; i < 50; i++ {
+		old, new := collection.New[int](), collection.New[int]()
+		for j := rand.Intn(20); j > 0; j-- {
+			old.Push(rand.Intn(4))
+		}
+		for j := rand.Intn(20); j > 0; j-- {
+			new.Push(rand.Intn(4))
+		}
+
+		patch, err := collection.CreateJSONPatch(old, new)
+		assert.Nil(t, err)
+		assert.Nil(t, old.ApplyJSONPatch(patch))
+		assert.Equal(t, append([]int{}, new.Items()...), append([]int{}, old.Items()...))
+	}
+}
@@ -0,0 +1,96 @@
+package collection
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// JSONStreamError describes a problem found while decoding a JSON array with
+// DecodeJSONArray. Offset is the number of bytes of input consumed when the
+// problem was found and Index is the position of the offending item within the
+// array, or -1 if the problem is not related to an item.
+type JSONStreamError struct {
+	Offset int64
+	Index  int
+	Err    error
+}
+
+func (e *JSONStreamError) Error() string {
+	if e.Index < 0 {
+		return fmt.Sprintf("collection: json array at offset %d: %v", e.Offset, e.Err)
+	}
+
+	return fmt.Sprintf("collection: json array item %d at offset %d: %v", e.Index, e.Offset, e.Err)
+}
+
+func (e *JSONStreamError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeJSONArray walks a JSON array read from the specified reader one item
+// at a time, handing the callback new collections of up to `batchSize` items
+// in their original order. Only a single batch is held in memory at once, so
+// arrays far larger than available memory can be processed. Decoding stops at
+// the first error returned by the callback, which is returned as is. Problems
+// with the input are reported as a *JSONStreamError.
+func DecodeJSONArray[T any](r io.Reader, batchSize int, f func(*Collection[T]) error) error {
+	if batchSize <= 0 {
+		return errors.New("collection: batch size must be greater than zero")
+	}
+
+	decoder := json.NewDecoder(r)
+
+	fail := func(index int, err error) error {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return &JSONStreamError{Offset: decoder.InputOffset(), Index: index, Err: err}
+	}
+
+	token, err := decoder.Token()
+	if err != nil {
+		return fail(-1, err)
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fail(-1, fmt.Errorf("expected array, found %v", token))
+	}
+
+	batch := New[T]()
+	for index := 0; decoder.More(); index++ {
+		var item T
+		if err := decoder.Decode(&item); err != nil {
+			return fail(index, err)
+		}
+		batch.Push(item)
+
+		if batch.Length() == batchSize {
+			if err := f(batch); err != nil {
+				return err
+			}
+			batch = New[T]()
+		}
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return fail(-1, err)
+	}
+
+	if !batch.IsEmpty() {
+		return f(batch)
+	}
+
+	return nil
+}
+
+// BatchWith returns a callback, suitable for DecodeJSONArray, which feeds each
+// collection it receives straight into Batch using the specified function and
+// batch size.
+func BatchWith[T any](f func(int, int, T), batchSize int) func(*Collection[T]) error {
+	return func(c *Collection[T]) error {
+		c.Batch(f, batchSize)
+		return nil
+	}
+}
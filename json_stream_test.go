@@ -0,0 +1,84 @@
+package collection_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wilhelm-murdoch/go-collection"
+)
+
+func TestDecodeJSONArray(t *testing.T) {
+	var sizes []int
+	var items []int
+
+	err := collection.DecodeJSONArray(strings.NewReader(` [1, 2, 3, 4, 5, 6, 7] `), 3, func(c *collection.Collection[int]) error {
+		sizes = append(sizes, c.Length())
+		items = append(items, c.Items()...)
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []int{3, 3, 1}, sizes, "Items should be delivered in fixed-size batches.")
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7}, items, "Items should be delivered in order.")
+}
+
+func TestDecodeJSONArrayEmpty(t *testing.T) {
+	calls := 0
+	err := collection.DecodeJSONArray(strings.NewReader(`[]`), 10, func(c *collection.Collection[Server]) error {
+		calls++
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 0, calls, "Empty arrays should not produce batches.")
+}
+
+func TestDecodeJSONArrayErrors(t *testing.T) {
+	noop := func(c *collection.Collection[Server]) error { return nil }
+
+	var streamErr *collection.JSONStreamError
+
+	err := collection.DecodeJSONArray(strings.NewReader(`{"host": "a"}`), 1, noop)
+	assert.True(t, errors.As(err, &streamErr))
+	assert.Equal(t, -1, streamErr.Index)
+
+	input := `[{"host": "a", "port": 1}, {"host": "b", "port": "two"}]`
+	err = collection.DecodeJSONArray(strings.NewReader(input), 1, noop)
+	if assert.True(t, errors.As(err, &streamErr)) {
+		assert.Equal(t, 1, streamErr.Index)
+		assert.Equal(t, int64(strings.LastIndex(input, `}`)+1), streamErr.Offset)
+	}
+
+	err = collection.DecodeJSONArray(strings.NewReader(`[{"host": "a"}, `), 1, noop)
+	assert.True(t, errors.As(err, &streamErr), "Truncated input should be reported.")
+
+	stop := errors.New("stop")
+	batches := 0
+	err = collection.DecodeJSONArray(strings.NewReader(`[1, 2, 3, 4]`), 2, func(c *collection.Collection[int]) error {
+		batches++
+		return stop
+	})
+	assert.Equal(t, stop, err, "Callback errors should be returned as is.")
+	assert.Equal(t, 1, batches, "Decoding should stop at the first callback error.")
+
+	assert.NotNil(t, collection.DecodeJSONArray(strings.NewReader(`[]`), 0, noop))
+}
+
+func TestDecodeJSONArrayBatchWith(t *testing.T) {
+	var input []string
+	for i := 0; i < 50; i++ {
+		input = append(input, fmt.Sprint(i))
+	}
+
+	var sum int64
+	err := collection.DecodeJSONArray(strings.NewReader("["+strings.Join(input, ",")+"]"), 10, collection.BatchWith(func(b, j int, n int) {
+		atomic.AddInt64(&sum, int64(n))
+	}, 5))
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1225), sum)
+}
@@ -0,0 +1,215 @@
+package collection
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// OverflowPolicy determines what a collection created with WithMaxLength does
+// when adding items would take it past its maximum length.
+type OverflowPolicy int
+
+const (
+	// OverflowReject drops the items which do not fit.
+	OverflowReject OverflowPolicy = iota
+	// OverflowEvict makes room by evicting items from the opposite end of the
+	// collection: the first items when appending or inserting, and the last
+	// items when unshifting.
+	OverflowEvict
+)
+
+// Option configures a collection created with NewWith.
+type Option[T any] func(*collectionOptions[T])
+
+type collectionOptions[T any] struct {
+	c        *Collection[T]
+	capacity int
+	items    []T
+}
+
+// NewWith returns a new collection of type T configured with the specified
+// options, which may be given in any order. ( Chainable )
+func NewWith[T any](opts ...Option[T]) *Collection[T] {
+	o := &collectionOptions[T]{c: New[T]()}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.capacity > 0 {
+		o.c.items = make([]T, 0, o.capacity)
+	}
+
+	// Initial items are added last so that they are subject to the configured
+	// length limit and validators.
+	o.c.insert(0, o.items...)
+
+	return o.c
+}
+
+// WithCapacity preallocates room for `n` items.
+func WithCapacity[T any](n int) Option[T] {
+	return func(o *collectionOptions[T]) {
+		o.capacity = n
+	}
+}
+
+// WithItems adds the specified items to the new collection. Unlike New, the
+// items are copied rather than sharing the specified slice.
+func WithItems[T any](items ...T) Option[T] {
+	return func(o *collectionOptions[T]) {
+		o.items = append(o.items, items...)
+	}
+}
+
+// WithEquality sets the function used to compare items, as described by
+// `collection.SetEquality`.
+func WithEquality[T any](eq func(a, b T) bool) Option[T] {
+	return func(o *collectionOptions[T]) {
+		o.c.equal = eq
+	}
+}
+
+// WithRand sets the source of randomness used by Random and RandomIndex,
+// which makes their results reproducible when seeded.
+func WithRand[T any](r *rand.Rand) Option[T] {
+	return func(o *collectionOptions[T]) {
+		o.c.rand = r
+	}
+}
+
+// WithLocking guards the collection's methods, and the functions which accept
+// it as an argument, with a read-write mutex so it can be shared between
+// goroutines. Callbacks passed to methods such as Each, Filter and Sort run
+// without the lock held, against a copy of the items, so they may freely read
+// the collection, though Sort keeps other writers waiting until it is done. Validators and equality functions run with the lock held
+// and must not use the collection. The slice returned by Items is not guarded.
+func WithLocking[T any]() Option[T] {
+	return func(o *collectionOptions[T]) {
+		o.c.mu, o.c.writing = &sync.RWMutex{}, &sync.Mutex{}
+	}
+}
+
+// WithMaxLength limits the collection to `n` items, handling additions which
+// would exceed it as determined by the specified policy. With OverflowReject,
+// the `Try` variants of the methods which add items report the items which do
+// not fit as ValidationErrors for the rule "maxLength", and add none of them.
+func WithMaxLength[T any](n int, policy OverflowPolicy) Option[T] {
+	return func(o *collectionOptions[T]) {
+		o.c.maxLength, o.c.overflow = n, policy
+	}
+}
+
+// WithValidator adds a function which every item must satisfy, by returning a
+// nil error, to be added to the collection by Push, PushDistinct, Unshift,
+// Concat or InsertAt. Items which fail validation are dropped; use the `Try`
+// variants of those methods to have them reported instead.
+func WithValidator[T any](f func(T) error) Option[T] {
+	return WithRule("", f)
+}
+
+// WithRule adds a named validation rule, as described by
+// `collection.AddValidator`.
+func WithRule[T any](rule string, f func(T) error) Option[T] {
+	return func(o *collectionOptions[T]) {
+		o.c.validators = append(o.c.validators, validationRule[T]{name: rule, check: f})
+	}
+}
+
+// WithUnique adds a validation rule, named "unique", requiring the key
+// returned by the specified function to be unique within the collection. The
+// keys are indexed as items are added and removed, so items must not be
+// changed through the slice returned by Items.
+func WithUnique[T any, K comparable](key func(T) K) Option[T] {
+	return func(o *collectionOptions[T]) {
+		o.c.validators = append(o.c.validators, validationRule[T]{
+			name: "unique",
+			key:  func(item T) any { return key(item) },
+		})
+	}
+}
+
+// unlocked is returned by lock and rlock for collections without a mutex.
+func unlocked() {}
+
+// lock acquires the collection's write lock, if it has one, returning the
+// function which releases it.
+func (c *Collection[T]) lock() func() {
+	if c.mu == nil {
+		return unlocked
+	}
+
+	c.writing.Lock()
+	c.mu.Lock()
+
+	return func() {
+		c.mu.Unlock()
+		c.writing.Unlock()
+	}
+}
+
+// rlock acquires the collection's read lock, if it has one, returning the
+// function which releases it.
+func (c *Collection[T]) rlock() func() {
+	if c.mu == nil {
+		return unlocked
+	}
+
+	c.mu.RLock()
+	return c.mu.RUnlock
+}
+
+// snapshot returns the collection's items for iterating over without holding
+// the lock, such as while running callbacks. Collections created with
+// WithLocking return a copy taken under the read lock.
+func (c *Collection[T]) snapshot() []T {
+	if c.mu == nil {
+		return c.items
+	}
+
+	defer c.rlock()()
+	return append([]T(nil), c.items...)
+}
+
+// insert adds the specified items at the specified index, which must be within
+// bounds, applying the collection's validators and length limit. The write
+// lock must be held.
+func (c *Collection[T]) insert(index int, items ...T) {
+	if len(c.validators) > 0 {
+		items, _ = c.validate(index, items)
+	}
+
+	if c.maxLength > 0 && c.overflow == OverflowReject {
+		room := c.maxLength - len(c.items)
+		if room < 0 {
+			room = 0
+		}
+
+		if len(items) > room {
+			items = items[:room]
+		}
+	}
+
+	if len(items) == 0 {
+		return
+	}
+	c.countKeys(items, 1)
+
+	appending := index == len(c.items)
+	if appending {
+		c.items = append(c.items, items...)
+	} else {
+		out := make([]T, 0, len(c.items)+len(items))
+		out = append(append(append(out, c.items[:index]...), items...), c.items[index:]...)
+		c.items = out
+	}
+
+	if excess := len(c.items) - c.maxLength; c.maxLength > 0 && excess > 0 {
+		if index == 0 && !appending {
+			c.countKeys(c.items[c.maxLength:], -1)
+			c.items = c.items[:c.maxLength]
+		} else {
+			c.countKeys(c.items[:excess], -1)
+			c.items = c.items[excess:]
+		}
+	}
+}
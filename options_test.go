@@ -0,0 +1,250 @@
+package collection_test
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wilhelm-murdoch/go-collection"
+	"gopkg.in/yaml.v3"
+)
+
+func TestNewWith(t *testing.T) {
+	items := []int{1, 2, 3}
+	c := collection.NewWith(
+		collection.WithItems(items...),
+		collection.WithCapacity[int](16),
+	)
+
+	assert.Equal(t, []int{1, 2, 3}, c.Items())
+	assert.Equal(t, 16, cap(c.Items()), "Options should apply regardless of their order.")
+
+	c.Unshift(0)
+	assert.Equal(t, []int{1, 2, 3}, items, "Items should be copied rather than shared.")
+
+	assert.True(t, collection.NewWith[int]().IsEmpty())
+}
+
+func TestNewWithEquality(t *testing.T) {
+	c := collection.NewWith(
+		collection.WithItems("Apple", "orange"),
+		collection.WithEquality(func(a, b string) bool { return len(a) == len(b) }),
+	)
+
+	assert.True(t, c.Contains("Peach"))
+}
+
+func TestNewWithRand(t *testing.T) {
+	pick := func() []int {
+		c := collection.NewWith(
+			collection.WithItems(1, 2, 3, 4, 5, 6, 7, 8, 9),
+			collection.WithRand[int](rand.New(rand.NewSource(42))),
+		)
+
+		var out []int
+		for i := 0; i < 10; i++ {
+			item, _ := c.Random()
+			out = append(out, item)
+		}
+
+		return out
+	}
+
+	assert.Equal(t, pick(), pick(), "Seeded sources should make random picks reproducible.")
+}
+
+func TestNewWithMaxLength(t *testing.T) {
+	reject := collection.NewWith(
+		collection.WithItems(1, 2, 3, 4),
+		collection.WithMaxLength[int](3, collection.OverflowReject),
+	)
+	assert.Equal(t, []int{1, 2, 3}, reject.Items())
+	assert.Equal(t, 3, reject.Push(5))
+	reject.InsertAt(9, 1)
+	assert.Equal(t, []int{1, 2, 3}, reject.Items())
+
+	evict := collection.NewWith(
+		collection.WithItems(1, 2, 3, 4),
+		collection.WithMaxLength[int](3, collection.OverflowEvict),
+	)
+	assert.Equal(t, []int{2, 3, 4}, evict.Items())
+
+	evict.Push(5)
+	assert.Equal(t, []int{3, 4, 5}, evict.Items(), "Appending should evict the first items.")
+
+	evict.Unshift(0)
+	assert.Equal(t, []int{0, 3, 4}, evict.Items(), "Unshifting should evict the last items.")
+
+	evict.InsertAt(9, 1).Concat([]int{7, 8})
+	assert.Equal(t, []int{4, 7, 8}, evict.Items())
+}
+
+func TestNewWithValidator(t *testing.T) {
+	c := collection.NewWith(
+		collection.WithItems(-1, 1, 2),
+		collection.WithValidator(func(i int) error {
+			if i < 0 {
+				return errors.New("must not be negative")
+			}
+			return nil
+		}),
+	)
+
+	assert.Equal(t, []int{1, 2}, c.Items())
+	assert.Equal(t, 3, c.Push(-4, 3))
+	assert.Equal(t, 3, c.Unshift(-1))
+	assert.Equal(t, 3, c.PushDistinct(3, -2))
+	assert.Equal(t, []int{1, 2, 3}, c.Concat([]int{-5}).InsertAt(-6, 1).Items())
+}
+
+func TestNewWithLocking(t *testing.T) {
+	c := collection.NewWith(collection.WithLocking[int]())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				c.Push(i*100 + j)
+				c.Contains(j)
+				c.Length()
+				c.PushDistinct(-1)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 801, c.Length())
+	assert.Equal(t, 1, c.Count(-1))
+}
+
+func TestNewWithLockingCallbacks(t *testing.T) {
+	c := collection.NewWith(collection.WithLocking[int](), collection.WithItems(3, 1, 2))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		c.Sort(func(i, j int) bool {
+			a, _ := c.At(i)
+			b, _ := c.At(j)
+			return a < b
+		})
+
+		c.Each(func(i int, item int) bool {
+			c.Push(item * 10)
+			return false
+		})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Callbacks which use the collection should not deadlock.")
+	}
+
+	assert.Equal(t, []int{1, 2, 3, 10, 20, 30}, c.Items())
+}
+
+func TestNewWithLockingSortWriters(t *testing.T) {
+	c := collection.NewWith(collection.WithLocking[int](), collection.WithItems(rand.Perm(1000)...))
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				c.Push(-1)
+				c.Pop()
+			}
+		}
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		c.Sort(func(i, j int) bool {
+			a, _ := c.At(i)
+			b, _ := c.At(j)
+			return a < b
+		})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Sort should finish while other goroutines keep writing.")
+	}
+}
+
+func TestNewWithLockingRace(t *testing.T) {
+	c := collection.NewWith(collection.WithLocking[Server](), collection.WithItems(Server{"a", 1}))
+	port := func(s Server) int { return s.Port }
+
+	readers := []func(){
+		func() { c.Paginate(1, 4) },
+		func() { _ = c.String() },
+		func() { _ = fmt.Sprintf("%+v %d", c, c) },
+		func() { c.Table(io.Discard, collection.TableOptions{}) },
+		func() { c.WriteCSV(io.Discard, collection.CSVOptions{}) },
+		func() { c.WriteBinary(io.Discard, collection.JSONElementCodec[Server]{}) },
+		func() { c.GobEncode() },
+		func() { json.Marshal(c) },
+		func() { xml.Marshal(c) },
+		func() { yaml.Marshal(c) },
+		func() { c.Value() },
+		func() { c.BinarySearch(Server{"a", 1}, func(a, b Server) bool { return a.Port < b.Port }) },
+		func() { c.Query().Collection() },
+		func() { c.Filter(func(s Server) bool { return c.Contains(s) }) },
+		func() { collection.IndexBy(c, port) },
+		func() { collection.Frequencies(c) },
+		func() { collection.Diff(c, c, func(a, b Server) bool { return a == b }) },
+	}
+
+	writers := []func(i int){
+		func(i int) { c.Push(Server{"b", i}) },
+		func(i int) { c.Reverse() },
+		func(i int) { c.Sort(func(i, j int) bool { a, _ := c.At(i); b, _ := c.At(j); return a.Port < b.Port }) },
+		func(i int) { c.Scan(`[{"host": "c", "port": 1}]`) },
+		func(i int) { c.UnmarshalJSON([]byte(`[{"host": "d", "port": 2}]`)) },
+		func(i int) { xml.Unmarshal([]byte(`<items><item host="d"></item></items>`), c) },
+		func(i int) { yaml.Unmarshal([]byte(`[{host: d}]`), c) },
+		func(i int) { data, _ := c.GobEncode(); c.GobDecode(data) },
+		func(i int) {
+			c.ApplyJSONPatch(collection.JSONPatch{{Op: "add", Path: "/-", Value: json.RawMessage(`{"host": "e"}`)}})
+		},
+		func(i int) {
+			c.Apply(collection.Diff(c, collection.New(Server{"f", i}), func(a, b Server) bool { return a == b }))
+		},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			for j := 0; j < 20; j++ {
+				readers[(i+j)%len(readers)]()
+				writers[(i+j)%len(writers)](j)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.False(t, c.IsEmpty())
+}
@@ -0,0 +1,315 @@
+package collection
+
+import (
+	"bytes"
+	"container/list"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// Entry is a single key/value pair held by an ordered map.
+type Entry[K comparable, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
+
+// OrderedMap is a map of keys of type K to values of type V which remembers the
+// order in which keys were first set.
+type OrderedMap[K comparable, V any] struct {
+	order   *list.List
+	entries map[K]*list.Element
+}
+
+// NewOrderedMap returns a new, empty, ordered map. ( Chainable )
+func NewOrderedMap[K comparable, V any]() *OrderedMap[K, V] {
+	return &OrderedMap[K, V]{
+		order:   list.New(),
+		entries: make(map[K]*list.Element),
+	}
+}
+
+// entry returns the entry held by the specified element of the map's order.
+func entry[K comparable, V any](element *list.Element) *Entry[K, V] {
+	return element.Value.(*Entry[K, V])
+}
+
+// Get returns the value associated with the specified key along with a boolean
+// value stating whether or not the key could be found.
+func (m *OrderedMap[K, V]) Get(key K) (value V, found bool) {
+	if element, found := m.entries[key]; found {
+		return entry[K, V](element).Value, true
+	}
+
+	return value, false
+}
+
+// Has returns true if the specified key is present in the current map.
+func (m *OrderedMap[K, V]) Has(key K) bool {
+	_, found := m.entries[key]
+	return found
+}
+
+// Set associates the specified value with the specified key. New keys are
+// appended to the end of the current map while existing keys keep their
+// position. ( Chainable )
+func (m *OrderedMap[K, V]) Set(key K, value V) *OrderedMap[K, V] {
+	if m.entries == nil {
+		m.order, m.entries = list.New(), make(map[K]*list.Element)
+	}
+
+	if element, found := m.entries[key]; found {
+		entry[K, V](element).Value = value
+		return m
+	}
+	m.entries[key] = m.order.PushBack(&Entry[K, V]{key, value})
+
+	return m
+}
+
+// Delete removes the specified key from the current map. Returns false if the
+// key could not be found.
+func (m *OrderedMap[K, V]) Delete(key K) bool {
+	element, found := m.entries[key]
+	if !found {
+		return false
+	}
+
+	delete(m.entries, key)
+	m.order.Remove(element)
+
+	return true
+}
+
+// Length returns number of keys associated with the current map.
+func (m *OrderedMap[K, V]) Length() int {
+	return len(m.entries)
+}
+
+// IsEmpty returns a boolean value describing the empty state of the current
+// map.
+func (m *OrderedMap[K, V]) IsEmpty() bool {
+	return m.Length() == 0
+}
+
+// each calls the specified function with every entry of the current map in
+// insertion order, until it returns true.
+func (m *OrderedMap[K, V]) each(f func(*Entry[K, V]) bool) {
+	if m.order == nil {
+		return
+	}
+
+	for element := m.order.Front(); element != nil; element = element.Next() {
+		if f(entry[K, V](element)) {
+			return
+		}
+	}
+}
+
+// Keys returns a new collection containing the current map's keys in insertion
+// order. ( Chainable )
+func (m *OrderedMap[K, V]) Keys() *Collection[K] {
+	out := make([]K, 0, m.Length())
+	m.each(func(e *Entry[K, V]) bool {
+		out = append(out, e.Key)
+		return false
+	})
+
+	return New(out...)
+}
+
+// Values returns a new collection containing the current map's values in
+// insertion order. ( Chainable )
+func (m *OrderedMap[K, V]) Values() *Collection[V] {
+	out := make([]V, 0, m.Length())
+	m.each(func(e *Entry[K, V]) bool {
+		out = append(out, e.Value)
+		return false
+	})
+
+	return New(out...)
+}
+
+// Entries returns a new collection containing the current map's key/value
+// pairs in insertion order. ( Chainable )
+func (m *OrderedMap[K, V]) Entries() *Collection[Entry[K, V]] {
+	out := make([]Entry[K, V], 0, m.Length())
+	m.each(func(e *Entry[K, V]) bool {
+		out = append(out, *e)
+		return false
+	})
+
+	return New(out...)
+}
+
+// Each iterates through the current map's key/value pairs in insertion order
+// and executes the specified callback on each pair. Iteration stops when the
+// callback returns true. ( Chainable )
+func (m *OrderedMap[K, V]) Each(f func(K, V) bool) *OrderedMap[K, V] {
+	m.each(func(e *Entry[K, V]) bool {
+		return f(e.Key, e.Value)
+	})
+
+	return m
+}
+
+// MarshalJSON implements the Marshaler interface so the current map can be
+// marshalled into a JSON object whose keys preserve insertion order. Keys must
+// be strings, integers or implement encoding.TextMarshaler.
+func (m *OrderedMap[K, V]) MarshalJSON() ([]byte, error) {
+	var (
+		buffer bytes.Buffer
+		err    error
+	)
+
+	buffer.WriteByte('{')
+	m.each(func(e *Entry[K, V]) bool {
+		if buffer.Len() > 1 {
+			buffer.WriteByte(',')
+		}
+
+		var name string
+		if name, err = encodeMapKey(e.Key); err != nil {
+			return true
+		}
+
+		var k, v []byte
+		if k, err = json.Marshal(name); err != nil {
+			return true
+		}
+
+		if v, err = json.Marshal(e.Value); err != nil {
+			return true
+		}
+
+		buffer.Write(k)
+		buffer.WriteByte(':')
+		buffer.Write(v)
+		return false
+	})
+	buffer.WriteByte('}')
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalJSON implements the Unmarshaler interface so a JSON object can be
+// unmarshalled into the current map, preserving the order of its keys.
+func (m *OrderedMap[K, V]) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	if token == nil {
+		return nil
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("collection: cannot unmarshal %v into an ordered map", token)
+	}
+
+	m.order, m.entries = list.New(), make(map[K]*list.Element)
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+
+		key, err := decodeMapKey[K](token.(string))
+		if err != nil {
+			return err
+		}
+
+		var value V
+		if err := decoder.Decode(&value); err != nil {
+			return err
+		}
+
+		m.Set(key, value)
+	}
+
+	_, err = decoder.Token()
+	return err
+}
+
+func encodeMapKey[K comparable](key K) (string, error) {
+	if marshaler, ok := any(key).(encoding.TextMarshaler); ok {
+		text, err := marshaler.MarshalText()
+		return string(text), err
+	}
+
+	v := reflect.ValueOf(key)
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	}
+
+	return "", fmt.Errorf("collection: unsupported map key type %T", key)
+}
+
+func decodeMapKey[K comparable](name string) (key K, err error) {
+	if unmarshaler, ok := any(&key).(encoding.TextUnmarshaler); ok {
+		err = unmarshaler.UnmarshalText([]byte(name))
+		return key, err
+	}
+
+	v := reflect.ValueOf(&key).Elem()
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(name)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(name, 10, v.Type().Bits())
+		if err != nil {
+			return key, err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(name, 10, v.Type().Bits())
+		if err != nil {
+			return key, err
+		}
+		v.SetUint(n)
+	default:
+		return key, fmt.Errorf("collection: unsupported map key type %T", key)
+	}
+
+	return key, nil
+}
+
+// IndexBy returns a new ordered map of the specified collection's items keyed
+// by the result of the specified key function. When several items share a key,
+// the last one wins while the key keeps the position of the first. ( Chainable )
+func IndexBy[T any, K comparable](c *Collection[T], key func(T) K) *OrderedMap[K, T] {
+	out := NewOrderedMap[K, T]()
+	for _, item := range c.snapshot() {
+		out.Set(key(item), item)
+	}
+
+	return out
+}
+
+// ToMap returns a new map of the specified collection's items keyed by the
+// result of the specified key function. When several items share a key, the
+// last one wins.
+func ToMap[T any, K comparable](c *Collection[T], key func(T) K) map[K]T {
+	items := c.snapshot()
+
+	out := make(map[K]T, len(items))
+	for _, item := range items {
+		out[key(item)] = item
+	}
+
+	return out
+}
@@ -0,0 +1,103 @@
+package collection_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wilhelm-murdoch/go-collection"
+)
+
+func TestOrderedMapSetGetDelete(t *testing.T) {
+	m := collection.NewOrderedMap[string, int]()
+	m.Set("banana", 2).Set("apple", 1).Set("cherry", 3).Set("banana", 20)
+
+	assert.Equal(t, 3, m.Length())
+	assert.Equal(t, []string{"banana", "apple", "cherry"}, m.Keys().Items(), "Keys should keep the order they were first set in.")
+	assert.Equal(t, []int{20, 1, 3}, m.Values().Items())
+
+	value, found := m.Get("banana")
+	assert.True(t, found)
+	assert.Equal(t, 20, value)
+
+	assert.True(t, m.Delete("apple"))
+	assert.False(t, m.Delete("apple"))
+	assert.False(t, m.Has("apple"))
+	assert.Equal(t, []string{"banana", "cherry"}, m.Keys().Items())
+}
+
+func TestOrderedMapDeleteMany(t *testing.T) {
+	m := collection.NewOrderedMap[int, int]()
+	for i := 0; i < 100000; i++ {
+		m.Set(i, i)
+	}
+
+	for i := 0; i < 100000; i += 2 {
+		assert.True(t, m.Delete(i))
+	}
+	assert.Equal(t, 50000, m.Length())
+
+	first, _ := m.Keys().AtFirst()
+	assert.Equal(t, 1, first)
+
+	m.Set(0, 0)
+	last, _ := m.Keys().AtLast()
+	assert.Equal(t, 0, last, "Keys set again after being deleted should move to the end.")
+
+	var zero collection.OrderedMap[string, int]
+	assert.False(t, zero.Delete("apple"))
+	assert.Equal(t, 0, zero.Keys().Length())
+	assert.Equal(t, 1, zero.Set("apple", 1).Length())
+}
+
+func TestOrderedMapEntries(t *testing.T) {
+	m := collection.NewOrderedMap[int, string]()
+	m.Set(3, "c").Set(1, "a")
+
+	assert.Equal(t, []collection.Entry[int, string]{{3, "c"}, {1, "a"}}, m.Entries().Items())
+
+	var keys []int
+	m.Each(func(k int, v string) bool {
+		keys = append(keys, k)
+		return true
+	})
+	assert.Equal(t, []int{3}, keys, "Each should stop when the callback returns true.")
+}
+
+func TestOrderedMapJSON(t *testing.T) {
+	m := collection.NewOrderedMap[string, int]()
+	m.Set("zebra", 1).Set("apple", 2).Set("mango", 3)
+
+	data, err := json.Marshal(m)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"zebra":1,"apple":2,"mango":3}`, string(data), "Marshalled keys should preserve insertion order.")
+
+	out := collection.NewOrderedMap[string, int]()
+	assert.Nil(t, json.Unmarshal(data, out))
+	assert.Equal(t, []string{"zebra", "apple", "mango"}, out.Keys().Items(), "Unmarshalled keys should preserve document order.")
+
+	numeric := collection.NewOrderedMap[int, bool]()
+	assert.Nil(t, json.Unmarshal([]byte(`{"10":true,"2":false}`), numeric))
+	assert.Equal(t, []int{10, 2}, numeric.Keys().Items())
+
+	assert.NotNil(t, json.Unmarshal([]byte(`[1,2]`), out), "Only JSON objects can be unmarshalled.")
+}
+
+func TestIndexBy(t *testing.T) {
+	type User struct {
+		ID   int
+		Name string
+	}
+
+	users := collection.New(User{2, "luke"}, User{1, "rob"}, User{2, "peter"})
+
+	indexed := collection.IndexBy(users, func(u User) int { return u.ID })
+	assert.Equal(t, []int{2, 1}, indexed.Keys().Items())
+
+	user, _ := indexed.Get(2)
+	assert.Equal(t, "peter", user.Name, "The last item sharing a key should win.")
+
+	m := collection.ToMap(users, func(u User) string { return u.Name })
+	assert.Len(t, m, 3)
+	assert.Equal(t, 1, m["rob"].ID)
+}
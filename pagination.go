@@ -0,0 +1,180 @@
+package collection
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// DefaultPerPage is the page size used by Paginate and After when a size below
+// 1 is requested.
+const DefaultPerPage = 20
+
+var (
+	// ErrInvalidCursor is returned when a pagination cursor cannot be decoded.
+	ErrInvalidCursor = errors.New("collection: invalid cursor")
+
+	// ErrCursorNotFound is returned when a pagination cursor made by
+	// EncodeCursor refers to an item which is no longer present in the
+	// collection. Cursors made by After resume from the item's last position
+	// instead.
+	ErrCursorNotFound = errors.New("collection: cursor not found")
+)
+
+// Page is a single page of items produced by Paginate, shaped for direct use
+// in API responses.
+type Page[T any] struct {
+	Items   []T  `json:"items"`
+	Page    int  `json:"page"`
+	PerPage int  `json:"perPage"`
+	Total   int  `json:"total"`
+	Pages   int  `json:"pages"`
+	HasNext bool `json:"hasNext"`
+	HasPrev bool `json:"hasPrev"`
+}
+
+// Paginate returns the items of the specified 1-based page, along with the
+// total item and page counts. Unlike Slice, it never panics: pages below 1 are
+// treated as the first page, page sizes below 1 fall back to DefaultPerPage
+// and pages past the end are returned empty.
+func (c *Collection[T]) Paginate(page, perPage int) Page[T] {
+	defer c.rlock()()
+
+	if page < 1 {
+		page = 1
+	}
+
+	if perPage < 1 {
+		perPage = DefaultPerPage
+	}
+
+	total := len(c.items)
+
+	pages := total / perPage
+	if total%perPage != 0 {
+		pages++
+	}
+
+	// Offsets are only computed for pages which exist, as huge page numbers
+	// would otherwise overflow.
+	from, to := total, total
+	if page <= pages {
+		from = (page - 1) * perPage
+		if total-from > perPage {
+			to = from + perPage
+		}
+	}
+
+	return Page[T]{
+		Items:   append([]T{}, c.items[from:to]...),
+		Page:    page,
+		PerPage: perPage,
+		Total:   total,
+		Pages:   pages,
+		HasNext: page < pages,
+		HasPrev: page > 1,
+	}
+}
+
+// CursorPage is a single page of items produced by After, shaped for direct
+// use in API responses. Next holds the cursor of the following page and is
+// empty on the last one.
+type CursorPage[T any] struct {
+	Items   []T    `json:"items"`
+	Next    string `json:"next,omitempty"`
+	HasNext bool   `json:"hasNext"`
+}
+
+// EncodeCursor encodes the specified key as an opaque, URL-safe cursor.
+func EncodeCursor[K any](key K) (string, error) {
+	data, err := json.Marshal(key)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor decodes a cursor produced by EncodeCursor back into its key.
+func DecodeCursor[K any](cursor string) (key K, err error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return key, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	if err := json.Unmarshal(data, &key); err != nil {
+		return key, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	return key, nil
+}
+
+// afterCursor is the cursor produced by After: the key of the last item of a
+// page along with the index it held, from which the next page resumes if the
+// item has since been removed.
+type afterCursor[K any] struct {
+	Key   K    `json:"k"`
+	Index *int `json:"i"`
+}
+
+// After returns up to `limit` items following the item identified by the
+// specified cursor, or from the start of the collection if the cursor is
+// empty. Items are identified by the stable key returned by the specified key
+// function, so pages remain consistent while items are added or removed
+// elsewhere in the collection. If the item itself was removed, the page starts
+// at the position it held, which skips or repeats items only if others before
+// it were removed or added too. Cursors made by EncodeCursor, which hold a
+// bare key, are also accepted. Limits below 1 fall back to DefaultPerPage.
+func After[T any, K comparable](c *Collection[T], key func(T) K, cursor string, limit int) (CursorPage[T], error) {
+	if limit < 1 {
+		limit = DefaultPerPage
+	}
+
+	items, from := c.snapshot(), 0
+	if cursor != "" {
+		after, err := DecodeCursor[afterCursor[K]](cursor)
+		if err != nil || after.Index == nil {
+			if after.Key, err = DecodeCursor[K](cursor); err != nil {
+				return CursorPage[T]{}, err
+			}
+			after.Index = nil
+		}
+
+		for from < len(items) && key(items[from]) != after.Key {
+			from++
+		}
+
+		switch {
+		case from < len(items):
+			from++
+		case after.Index == nil:
+			return CursorPage[T]{}, ErrCursorNotFound
+		case *after.Index < 0:
+			from = 0
+		case *after.Index < len(items):
+			from = *after.Index
+		}
+	}
+
+	to := len(items)
+	if len(items)-from > limit {
+		to = from + limit
+	}
+
+	out := CursorPage[T]{
+		Items:   append([]T{}, items[from:to]...),
+		HasNext: to < len(items),
+	}
+
+	if out.HasNext {
+		index := to - 1
+		next, err := EncodeCursor(afterCursor[K]{key(items[index]), &index})
+		if err != nil {
+			return CursorPage[T]{}, err
+		}
+		out.Next = next
+	}
+
+	return out, nil
+}
@@ -0,0 +1,116 @@
+package collection_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wilhelm-murdoch/go-collection"
+)
+
+func returnNumbers(n int) *collection.Collection[int] {
+	c := collection.New[int]()
+	for i := 1; i <= n; i++ {
+		c.Push(i)
+	}
+
+	return c
+}
+
+func TestCollectionPaginate(t *testing.T) {
+	c := returnNumbers(7)
+
+	assert.Equal(t, collection.Page[int]{
+		Items: []int{4, 5, 6}, Page: 2, PerPage: 3, Total: 7, Pages: 3, HasNext: true, HasPrev: true,
+	}, c.Paginate(2, 3))
+
+	last := c.Paginate(3, 3)
+	assert.Equal(t, []int{7}, last.Items)
+	assert.False(t, last.HasNext)
+
+	assert.Equal(t, []int{1, 2, 3}, c.Paginate(-1, 3).Items, "Pages below 1 should be treated as the first page.")
+	assert.Equal(t, 20, c.Paginate(1, 0).PerPage)
+	assert.Equal(t, []int{}, c.Paginate(9, 3).Items, "Pages past the end should be empty rather than panic.")
+	assert.Equal(t, []int{}, c.Paginate(1<<62, 4).Items, "Huge pages should not overflow.")
+	assert.Equal(t, 1, c.Paginate(1, math.MaxInt).Pages, "Huge page sizes should not overflow.")
+
+	data, err := json.Marshal(collection.New[int]().Paginate(1, 10))
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"items":[],"page":1,"perPage":10,"total":0,"pages":0,"hasNext":false,"hasPrev":false}`, string(data))
+}
+
+func TestAfter(t *testing.T) {
+	servers := collection.New(Server{"a", 1}, Server{"b", 2}, Server{"c", 3}, Server{"d", 4}, Server{"e", 5})
+	host := func(s Server) string { return s.Host }
+
+	var (
+		hosts  []string
+		cursor string
+	)
+
+	for {
+		page, err := collection.After(servers, host, cursor, 2)
+		assert.Nil(t, err)
+
+		for _, server := range page.Items {
+			hosts = append(hosts, server.Host)
+		}
+
+		if !page.HasNext {
+			assert.Empty(t, page.Next)
+			break
+		}
+
+		assert.Equal(t, url.QueryEscape(page.Next), page.Next, "Cursors should be URL-safe.")
+		cursor = page.Next
+
+		// Items removed before the cursor should not shift later pages.
+		servers.Shift()
+	}
+
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, hosts)
+}
+
+func TestAfterRemovedCursor(t *testing.T) {
+	servers := collection.New(Server{"a", 1}, Server{"b", 2}, Server{"c", 3}, Server{"d", 4}, Server{"e", 5})
+	host := func(s Server) string { return s.Host }
+
+	page, err := collection.After(servers, host, "", 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []Server{{"a", 1}, {"b", 2}}, page.Items)
+
+	// Removing the item the cursor points to should not strand the client.
+	servers.RemoveAt(1)
+
+	page, err = collection.After(servers, host, page.Next, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []Server{{"c", 3}, {"d", 4}}, page.Items)
+
+	servers.Pop()
+	servers.Pop()
+
+	page, err = collection.After(servers, host, page.Next, 2)
+	assert.Nil(t, err)
+	assert.Empty(t, page.Items, "Cursors past the end should give an empty last page.")
+	assert.False(t, page.HasNext)
+}
+
+func TestAfterErrors(t *testing.T) {
+	host := func(s Server) string { return s.Host }
+
+	_, err := collection.After(collection.New(Server{"a", 1}), host, "not a cursor!", 2)
+	assert.True(t, errors.Is(err, collection.ErrInvalidCursor))
+
+	cursor, err := collection.EncodeCursor("z")
+	assert.Nil(t, err)
+
+	_, err = collection.After(collection.New(Server{"a", 1}), host, cursor, 2)
+	assert.True(t, errors.Is(err, collection.ErrCursorNotFound))
+
+	key, err := collection.DecodeCursor[string](cursor)
+	assert.Nil(t, err)
+	assert.Equal(t, "z", key)
+}
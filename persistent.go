@@ -0,0 +1,645 @@
+package collection
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	persistentSnapshotName = "snapshot"
+	persistentLogName      = "wal"
+
+	// persistentHeaderSize is the size of the header framing each record: its
+	// length, the CRC-32 checksum of its payload and that of the header itself.
+	persistentHeaderSize = 12
+)
+
+// persistentOp identifies a mutation recorded in the write-ahead log.
+type persistentOp byte
+
+const (
+	persistentPush persistentOp = iota + 1
+	persistentInsertAt
+	persistentPop
+	persistentShift
+	persistentEmpty
+	persistentGeneration
+)
+
+// ErrClosed is returned when using a Persistent collection after it has been
+// closed.
+var ErrClosed = errors.New("collection: persistent collection is closed")
+
+// SyncPolicy determines how often a Persistent collection flushes its
+// write-ahead log to stable storage.
+type SyncPolicy int
+
+const (
+	// SyncAlways flushes the log after every mutation, so that acknowledged
+	// writes survive a crash of the whole machine.
+	SyncAlways SyncPolicy = iota
+	// SyncInterval flushes the log periodically, as configured by
+	// PersistentOptions.SyncInterval, bounding how much may be lost.
+	SyncInterval
+	// SyncNever leaves flushing to the operating system. Writes survive a crash
+	// of the process, but not necessarily of the machine.
+	SyncNever
+)
+
+// PersistentOptions configures a Persistent collection.
+type PersistentOptions[T any] struct {
+	// Codec encodes items in the log and snapshots. Defaults to
+	// JSONElementCodec.
+	Codec ElementCodec[T]
+
+	// Sync determines how often the log is flushed. Defaults to SyncAlways.
+	Sync SyncPolicy
+
+	// SyncInterval is the flush period used by SyncInterval. Defaults to one
+	// second.
+	SyncInterval time.Duration
+
+	// CompactSize is the size in bytes the log may reach before it is
+	// automatically compacted into a snapshot. Zero disables automatic
+	// compaction.
+	CompactSize int64
+}
+
+// Persistent is a collection stored in a directory on local disk, which
+// survives restarts. Mutations are appended to a write-ahead log before being
+// applied in memory, and the log is periodically compacted into a snapshot.
+// It is safe for concurrent use.
+//
+// Every log starts with a generation number, and each snapshot records the
+// generation of the last log it covers, so a log which was already compacted
+// is never replayed twice.
+type Persistent[T any] struct {
+	mu         sync.Mutex
+	dir        string
+	opts       PersistentOptions[T]
+	c          *Collection[T]
+	log        *os.File
+	generation uint64
+	size       int64
+	dirty      bool
+	closed     bool
+	stop       chan struct{}
+	stopped    sync.WaitGroup
+}
+
+// OpenPersistent opens the persistent collection stored in the specified
+// directory, creating it if needed. The latest snapshot is loaded and the log
+// replayed on top of it. A torn or corrupted final record of the log, as left
+// behind by a crash mid-write, is detected by its checksum and discarded. Any
+// other record which cannot be read or applied, such as one written with a
+// different codec or whose length was corrupted, is reported as an error and
+// the log is left untouched.
+func OpenPersistent[T any](dir string, opts PersistentOptions[T]) (*Persistent[T], error) {
+	if opts.Codec == nil {
+		opts.Codec = JSONElementCodec[T]{}
+	}
+
+	if opts.SyncInterval <= 0 {
+		opts.SyncInterval = time.Second
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	p := &Persistent[T]{dir: dir, opts: opts, c: New[T]()}
+
+	covered, err := p.loadSnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	log, err := os.OpenFile(filepath.Join(dir, persistentLogName), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	p.log = log
+
+	if err := p.replay(covered); err != nil {
+		log.Close()
+		return nil, err
+	}
+
+	if opts.Sync == SyncInterval {
+		p.stop = make(chan struct{})
+		p.stopped.Add(1)
+		go p.syncLoop()
+	}
+
+	return p, nil
+}
+
+// loadSnapshot loads the snapshot, if there is one, and returns the generation
+// of the last log it covers.
+func (p *Persistent[T]) loadSnapshot() (uint64, error) {
+	file, err := os.Open(filepath.Join(p.dir, persistentSnapshotName))
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	r := bufio.NewReader(file)
+
+	header, err := readPersistentRecord(r, info.Size())
+	if err != nil {
+		return 0, fmt.Errorf("collection: reading snapshot: %w", err)
+	}
+
+	generation, err := parseGeneration(header)
+	if err != nil {
+		return 0, fmt.Errorf("collection: reading snapshot: %w", err)
+	}
+
+	c, err := ReadBinary(r, p.opts.Codec)
+	if err != nil {
+		return 0, fmt.Errorf("collection: reading snapshot: %w", err)
+	}
+
+	p.c = c
+	return generation, nil
+}
+
+// replay applies every record of the log on top of the snapshot, which covers
+// every log up to the specified generation, and truncates a torn final record.
+// A log the snapshot already covers is discarded rather than replayed.
+func (p *Persistent[T]) replay(covered uint64) error {
+	info, err := p.log.Stat()
+	if err != nil {
+		return err
+	}
+
+	r := bufio.NewReader(p.log)
+
+	for {
+		record, err := readPersistentRecord(r, info.Size()-p.size)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+
+		// Only the final record can have been torn by a crash, so a bad
+		// checksum anywhere else is genuine corruption. A record with an
+		// intact header which runs past the end of the log is torn too.
+		if errors.Is(err, ErrChecksumMismatch) && p.size+int64(len(record))+persistentHeaderSize == info.Size() {
+			break
+		}
+
+		if err != nil {
+			return fmt.Errorf("collection: replaying log at offset %d: %w", p.size, err)
+		}
+
+		if p.size == 0 {
+			if p.generation, err = parseGeneration(record); err != nil {
+				return fmt.Errorf("collection: replaying log: %w", err)
+			}
+
+			if p.generation <= covered {
+				break
+			}
+		} else if err := p.apply(record); err != nil {
+			return fmt.Errorf("collection: replaying log at offset %d: %w", p.size, err)
+		}
+
+		p.size += int64(len(record)) + persistentHeaderSize
+	}
+
+	// A log without a header, or one which was compacted just before a
+	// crash, is started over.
+	if p.size == 0 || p.generation <= covered {
+		return p.startLog(covered + 1)
+	}
+
+	if err := p.log.Truncate(p.size); err != nil {
+		return err
+	}
+
+	_, err = p.log.Seek(p.size, io.SeekStart)
+	return err
+}
+
+// startLog empties the log and starts it over with the specified generation.
+func (p *Persistent[T]) startLog(generation uint64) error {
+	if err := p.log.Truncate(0); err != nil {
+		return err
+	}
+
+	if _, err := p.log.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	p.size = 0
+
+	if err := p.appendRecord(generationRecord(generation)); err != nil {
+		return err
+	}
+	p.generation = generation
+
+	return p.syncLocked()
+}
+
+func generationRecord(generation uint64) []byte {
+	return binary.AppendUvarint([]byte{byte(persistentGeneration)}, generation)
+}
+
+func parseGeneration(record []byte) (uint64, error) {
+	if len(record) == 0 || persistentOp(record[0]) != persistentGeneration {
+		return 0, errors.New("collection: missing generation header")
+	}
+
+	generation, n := binary.Uvarint(record[1:])
+	if n <= 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	return generation, nil
+}
+
+// frameRecord prefixes the specified record with its header.
+func frameRecord(record []byte) []byte {
+	framed := make([]byte, persistentHeaderSize, persistentHeaderSize+len(record))
+	binary.LittleEndian.PutUint32(framed[:4], uint32(len(record)))
+	binary.LittleEndian.PutUint32(framed[4:], crc32.ChecksumIEEE(record))
+	binary.LittleEndian.PutUint32(framed[8:], crc32.ChecksumIEEE(framed[:8]))
+
+	return append(framed, record...)
+}
+
+// readPersistentRecord reads a single record, made up of its header and its
+// payload, from the specified number of remaining bytes. ErrChecksumMismatch
+// is returned, along with the payload, if it is corrupt, and without it if
+// the header is. A record with an intact header running past the remaining
+// bytes was torn, and results in io.ErrUnexpectedEOF.
+func readPersistentRecord(r io.Reader, remaining int64) ([]byte, error) {
+	var header [persistentHeaderSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+
+	if crc32.ChecksumIEEE(header[:8]) != binary.LittleEndian.Uint32(header[8:]) {
+		return nil, ErrChecksumMismatch
+	}
+
+	length := int64(binary.LittleEndian.Uint32(header[:4]))
+	if length > remaining-persistentHeaderSize {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	record := make([]byte, length)
+	if _, err := io.ReadFull(r, record); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+
+	if crc32.ChecksumIEEE(record) != binary.LittleEndian.Uint32(header[4:8]) {
+		return record, ErrChecksumMismatch
+	}
+
+	return record, nil
+}
+
+// apply replays the specified log record onto the in-memory collection.
+func (p *Persistent[T]) apply(record []byte) error {
+	if len(record) == 0 {
+		return io.ErrUnexpectedEOF
+	}
+
+	op, data := persistentOp(record[0]), record[1:]
+
+	switch op {
+	case persistentPush, persistentInsertAt:
+		index, n := binary.Uvarint(data)
+		if n <= 0 {
+			return io.ErrUnexpectedEOF
+		}
+
+		item, err := p.opts.Codec.DecodeElement(data[n:])
+		if err != nil {
+			return err
+		}
+
+		if op == persistentPush {
+			p.c.Push(item)
+		} else {
+			p.c.InsertAt(item, int(index))
+		}
+	case persistentPop:
+		p.c.Pop()
+	case persistentShift:
+		if !p.c.IsEmpty() {
+			p.c.Shift()
+		}
+	case persistentEmpty:
+		p.c.Empty()
+	default:
+		return fmt.Errorf("collection: unknown log operation %d", op)
+	}
+
+	return nil
+}
+
+// write appends a record to the log and applies it in memory. The lock must be
+// held.
+func (p *Persistent[T]) write(op persistentOp, index int, item *T) error {
+	if p.closed {
+		return ErrClosed
+	}
+
+	record := []byte{byte(op)}
+	if item != nil {
+		record = binary.AppendUvarint(record, uint64(index))
+
+		var err error
+		if record, err = p.opts.Codec.AppendElement(record, *item); err != nil {
+			return err
+		}
+	}
+
+	size := p.size
+	if err := p.appendRecord(record); err != nil {
+		return err
+	}
+
+	// A record which cannot be applied would also fail to replay, so it is
+	// dropped from the log.
+	if err := p.apply(record); err != nil {
+		p.truncateLog(size)
+		return err
+	}
+
+	if p.opts.Sync == SyncAlways {
+		if err := p.syncLocked(); err != nil {
+			return err
+		}
+	}
+
+	if p.opts.CompactSize > 0 && p.size >= p.opts.CompactSize {
+		return p.compactLocked()
+	}
+
+	return nil
+}
+
+// appendRecord frames the specified record and appends it to the log. The lock
+// must be held.
+func (p *Persistent[T]) appendRecord(record []byte) error {
+	framed := frameRecord(record)
+
+	if _, err := p.log.Write(framed); err != nil {
+		// Drop any partially written record so later ones remain readable.
+		p.truncateLog(p.size)
+		return err
+	}
+	p.size += int64(len(framed))
+	p.dirty = true
+
+	return nil
+}
+
+func (p *Persistent[T]) truncateLog(size int64) {
+	p.log.Truncate(size)
+	p.log.Seek(size, io.SeekStart)
+	p.size = size
+}
+
+// Push appends the specified items to the collection and returns its new
+// length.
+func (p *Persistent[T]) Push(items ...T) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i := range items {
+		if err := p.write(persistentPush, 0, &items[i]); err != nil {
+			return p.c.Length(), err
+		}
+	}
+
+	return p.c.Length(), nil
+}
+
+// InsertAt inserts the specified item at the specified index, following the
+// rules of `Collection.InsertAt`.
+func (p *Persistent[T]) InsertAt(item T, index int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if index < 0 {
+		index = 0
+	}
+
+	return p.write(persistentInsertAt, index, &item)
+}
+
+// Pop removes and returns the last item of the collection, if there is one.
+func (p *Persistent[T]) Pop() (out T, found bool, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if out, found = p.c.AtLast(); !found {
+		return out, false, nil
+	}
+
+	if err := p.write(persistentPop, 0, nil); err != nil {
+		return out, false, err
+	}
+
+	return out, true, nil
+}
+
+// Shift removes and returns the first item of the collection, if there is
+// one.
+func (p *Persistent[T]) Shift() (out T, found bool, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if out, found = p.c.AtFirst(); !found {
+		return out, false, nil
+	}
+
+	if err := p.write(persistentShift, 0, nil); err != nil {
+		return out, false, err
+	}
+
+	return out, true, nil
+}
+
+// Empty removes every item from the collection.
+func (p *Persistent[T]) Empty() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.write(persistentEmpty, 0, nil)
+}
+
+// Length returns the number of items in the collection.
+func (p *Persistent[T]) Length() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.c.Length()
+}
+
+// Items returns a copy of the collection's items.
+func (p *Persistent[T]) Items() []T {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]T(nil), p.c.items...)
+}
+
+// Collection returns an in-memory copy of the collection.
+func (p *Persistent[T]) Collection() *Collection[T] {
+	return New(p.Items()...)
+}
+
+// Sync flushes the log to stable storage.
+func (p *Persistent[T]) Sync() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return ErrClosed
+	}
+
+	return p.syncLocked()
+}
+
+func (p *Persistent[T]) syncLocked() error {
+	if !p.dirty {
+		return nil
+	}
+
+	if err := p.log.Sync(); err != nil {
+		return err
+	}
+
+	p.dirty = false
+	return nil
+}
+
+func (p *Persistent[T]) syncLoop() {
+	defer p.stopped.Done()
+
+	ticker := time.NewTicker(p.opts.SyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.Sync()
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+// Compact writes the current items to a new snapshot and starts a new log.
+// The snapshot is written to a temporary file and renamed into place, so a
+// crash part way through leaves the previous snapshot and log intact, and a
+// crash after the rename leaves a log the new snapshot is known to cover.
+func (p *Persistent[T]) Compact() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return ErrClosed
+	}
+
+	return p.compactLocked()
+}
+
+func (p *Persistent[T]) compactLocked() error {
+	file, err := os.CreateTemp(p.dir, persistentSnapshotName+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	w := bufio.NewWriter(file)
+	if _, err := w.Write(frameRecord(generationRecord(p.generation))); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := p.c.WriteBinary(w, p.opts.Codec); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := w.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(file.Name(), filepath.Join(p.dir, persistentSnapshotName)); err != nil {
+		return err
+	}
+
+	if err := syncDir(p.dir); err != nil {
+		return err
+	}
+
+	// The snapshot now holds everything the log did, so the log can start over.
+	return p.startLog(p.generation + 1)
+}
+
+// syncDir flushes a directory's entries, making renames within it durable.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	return d.Sync()
+}
+
+// Close flushes and closes the log. The collection cannot be used afterwards.
+func (p *Persistent[T]) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return ErrClosed
+	}
+
+	err := p.syncLocked()
+	if closeErr := p.log.Close(); err == nil {
+		err = closeErr
+	}
+	p.closed = true
+	p.mu.Unlock()
+
+	if p.stop != nil {
+		close(p.stop)
+		p.stopped.Wait()
+	}
+
+	return err
+}
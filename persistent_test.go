@@ -0,0 +1,223 @@
+package collection_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wilhelm-murdoch/go-collection"
+)
+
+func TestPersistentReplay(t *testing.T) {
+	dir := t.TempDir()
+
+	p, err := collection.OpenPersistent(dir, collection.PersistentOptions[Server]{})
+	assert.Nil(t, err)
+
+	length, err := p.Push(Server{"a", 1}, Server{"b", 2}, Server{"c", 3})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, length)
+
+	assert.Nil(t, p.InsertAt(Server{"z", 0}, 1))
+
+	last, found, err := p.Pop()
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, Server{"c", 3}, last)
+
+	first, found, err := p.Shift()
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, Server{"a", 1}, first)
+
+	assert.Nil(t, p.Close())
+
+	p, err = collection.OpenPersistent(dir, collection.PersistentOptions[Server]{})
+	assert.Nil(t, err)
+	assert.Equal(t, []Server{{"z", 0}, {"b", 2}}, p.Items(), "Reopening should replay the log.")
+
+	assert.Nil(t, p.Empty())
+	_, found, err = p.Pop()
+	assert.Nil(t, err)
+	assert.False(t, found)
+	assert.Nil(t, p.Close())
+
+	p, err = collection.OpenPersistent(dir, collection.PersistentOptions[Server]{})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, p.Length())
+	assert.Nil(t, p.Close())
+
+	_, err = p.Push(Server{"a", 1})
+	assert.True(t, errors.Is(err, collection.ErrClosed))
+}
+
+func TestPersistentCompact(t *testing.T) {
+	dir := t.TempDir()
+	opts := collection.PersistentOptions[int]{
+		Codec:       collection.GobElementCodec[int]{},
+		Sync:        collection.SyncNever,
+		CompactSize: 256,
+	}
+
+	p, err := collection.OpenPersistent(dir, opts)
+	assert.Nil(t, err)
+
+	for i := 0; i < 100; i++ {
+		_, err := p.Push(i)
+		assert.Nil(t, err)
+	}
+
+	for i := 0; i < 50; i++ {
+		_, _, err := p.Shift()
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, p.Close())
+
+	log, err := os.Stat(filepath.Join(dir, "wal"))
+	assert.Nil(t, err)
+	assert.Less(t, log.Size(), int64(256), "The log should have been compacted into the snapshot.")
+
+	p, err = collection.OpenPersistent(dir, opts)
+	assert.Nil(t, err)
+	assert.Equal(t, 50, p.Length())
+
+	first, _, _ := p.Shift()
+	assert.Equal(t, 50, first)
+
+	assert.Nil(t, p.Compact())
+	assert.Nil(t, p.Close())
+
+	p, err = collection.OpenPersistent(dir, opts)
+	assert.Nil(t, err)
+	assert.Equal(t, 49, p.Length())
+	assert.Equal(t, 51, p.Collection().Items()[0])
+	assert.Nil(t, p.Close())
+}
+
+func TestPersistentTornWrite(t *testing.T) {
+	dir := t.TempDir()
+
+	p, err := collection.OpenPersistent(dir, collection.PersistentOptions[string]{Sync: collection.SyncInterval})
+	assert.Nil(t, err)
+	_, err = p.Push("apple", "orange")
+	assert.Nil(t, err)
+	assert.Nil(t, p.Close())
+
+	path := filepath.Join(dir, "wal")
+	intact, err := os.ReadFile(path)
+	assert.Nil(t, err)
+
+	// Simulate a crash part way through appending a third record, by writing
+	// the start of a copy of the last one.
+	torn := append(append([]byte{}, intact...), intact[len(intact)-22:len(intact)-5]...)
+	assert.Nil(t, os.WriteFile(path, torn, 0o644))
+
+	p, err = collection.OpenPersistent(dir, collection.PersistentOptions[string]{})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"apple", "orange"}, p.Items())
+
+	_, err = p.Push("banana")
+	assert.Nil(t, err)
+	assert.Nil(t, p.Close())
+
+	// Corrupt the payload of the last record so its checksum no longer matches.
+	data, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	data[len(data)-2] ^= 0xff
+	assert.Nil(t, os.WriteFile(path, data, 0o644))
+
+	p, err = collection.OpenPersistent(dir, collection.PersistentOptions[string]{})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"apple", "orange"}, p.Items(), "Corrupted records should be discarded.")
+	assert.Nil(t, p.Close())
+}
+
+func TestPersistentCompactCrash(t *testing.T) {
+	dir := t.TempDir()
+
+	p, err := collection.OpenPersistent(dir, collection.PersistentOptions[int]{})
+	assert.Nil(t, err)
+	_, err = p.Push(1, 2, 3)
+	assert.Nil(t, err)
+
+	path := filepath.Join(dir, "wal")
+	log, err := os.ReadFile(path)
+	assert.Nil(t, err)
+
+	assert.Nil(t, p.Compact())
+	assert.Nil(t, p.Close())
+
+	// Simulate a crash after the snapshot was renamed into place but before
+	// the log was started over.
+	assert.Nil(t, os.WriteFile(path, log, 0o644))
+
+	p, err = collection.OpenPersistent(dir, collection.PersistentOptions[int]{})
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1, 2, 3}, p.Items(), "A log covered by the snapshot should not be replayed.")
+
+	_, err = p.Push(4)
+	assert.Nil(t, err)
+	assert.Nil(t, p.Close())
+
+	p, err = collection.OpenPersistent(dir, collection.PersistentOptions[int]{})
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4}, p.Items())
+	assert.Nil(t, p.Close())
+}
+
+func TestPersistentReplayErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	p, err := collection.OpenPersistent(dir, collection.PersistentOptions[string]{})
+	assert.Nil(t, err)
+	_, err = p.Push("a", "b")
+	assert.Nil(t, err)
+	assert.Nil(t, p.Close())
+
+	_, err = collection.OpenPersistent(dir, collection.PersistentOptions[string]{Codec: collection.GobElementCodec[string]{}})
+	assert.NotNil(t, err, "Records written with another codec should be reported.")
+
+	p, err = collection.OpenPersistent(dir, collection.PersistentOptions[string]{})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b"}, p.Items(), "A failed replay should leave the log intact.")
+	assert.Nil(t, p.Close())
+
+	// Corrupt the payload of a record before the last one.
+	path := filepath.Join(dir, "wal")
+	data, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	data[len(data)-19] ^= 0xff
+	assert.Nil(t, os.WriteFile(path, data, 0o644))
+
+	_, err = collection.OpenPersistent(dir, collection.PersistentOptions[string]{})
+	assert.True(t, errors.Is(err, collection.ErrChecksumMismatch), "Corruption before the end of the log should be reported.")
+}
+
+func TestPersistentCorruptLength(t *testing.T) {
+	dir := t.TempDir()
+
+	p, err := collection.OpenPersistent(dir, collection.PersistentOptions[string]{})
+	assert.Nil(t, err)
+	_, err = p.Push("a", "b", "c")
+	assert.Nil(t, err)
+	assert.Nil(t, p.Close())
+
+	// Each record is made up of a 12 byte header and a 5 byte payload, so this
+	// overwrites the length of the record holding "b" with one running past
+	// the end of the log.
+	path := filepath.Join(dir, "wal")
+	data, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	data[len(data)-34+2] = 0xff
+	assert.Nil(t, os.WriteFile(path, data, 0o644))
+
+	_, err = collection.OpenPersistent(dir, collection.PersistentOptions[string]{})
+	assert.True(t, errors.Is(err, collection.ErrChecksumMismatch), "A corrupt length before the end of the log should be reported.")
+	assert.Contains(t, err.Error(), "offset")
+
+	after, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, data, after, "The records after a corrupt length should be kept.")
+}
@@ -0,0 +1,168 @@
+package collection
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// PriorityQueue is a binary heap of items of type T. The item considered the
+// "least" by the queue's less function always sits at the top of the queue.
+type PriorityQueue[T any] struct {
+	h *binaryHeap[T]
+}
+
+// NewPriorityQueue returns a new priority queue ordered by the specified less
+// function and containing the specified items. ( Chainable )
+func NewPriorityQueue[T any](less func(a, b T) bool, items ...T) *PriorityQueue[T] {
+	h := &binaryHeap[T]{
+		items: append([]T(nil), items...),
+		less:  less,
+	}
+	heap.Init(h)
+
+	return &PriorityQueue[T]{h: h}
+}
+
+// Items returns the current queue's set of items in heap order.
+func (q *PriorityQueue[T]) Items() []T {
+	return q.h.items
+}
+
+// Length returns number of items associated with the current queue.
+func (q *PriorityQueue[T]) Length() int {
+	return q.h.Len()
+}
+
+// IsEmpty returns a boolean value describing the empty state of the current
+// queue.
+func (q *PriorityQueue[T]) IsEmpty() bool {
+	return q.Length() == 0
+}
+
+// Push adds one or more items to the current queue, returning the new length.
+func (q *PriorityQueue[T]) Push(items ...T) int {
+	for _, item := range items {
+		heap.Push(q.h, item)
+	}
+
+	return q.Length()
+}
+
+// Pop removes the top item from the current queue and then returns that item
+// along with a boolean value stating whether or not an item could be found.
+func (q *PriorityQueue[T]) Pop() (out T, found bool) {
+	if q.IsEmpty() {
+		return
+	}
+
+	return heap.Pop(q.h).(T), true
+}
+
+// Peek returns the top item of the current queue without removing it along
+// with a boolean value stating whether or not an item could be found.
+func (q *PriorityQueue[T]) Peek() (out T, found bool) {
+	if q.IsEmpty() {
+		return
+	}
+
+	return q.h.items[0], true
+}
+
+// Update re-establishes the heap ordering after the item at the specified index
+// has changed its priority. Returns false if the index is out of range.
+func (q *PriorityQueue[T]) Update(index int) bool {
+	if index < 0 || index >= q.Length() {
+		return false
+	}
+
+	heap.Fix(q.h, index)
+	return true
+}
+
+// Remove removes the item at the specified index from the current queue and
+// then returns that item along with a boolean value stating whether or not an
+// item could be found.
+func (q *PriorityQueue[T]) Remove(index int) (out T, found bool) {
+	if index < 0 || index >= q.Length() {
+		return
+	}
+
+	return heap.Remove(q.h, index).(T), true
+}
+
+// Collection drains a copy of the current queue into a new collection ordered
+// from top to bottom. The current queue is left untouched. ( Chainable )
+func (q *PriorityQueue[T]) Collection() *Collection[T] {
+	clone := NewPriorityQueue(q.h.less, q.h.items...)
+
+	out := New[T]()
+	for !clone.IsEmpty() {
+		item, _ := clone.Pop()
+		out.Push(item)
+	}
+
+	return out
+}
+
+// TopK returns a new collection containing the `k` greatest items of the
+// specified collection as defined by the less function, ordered from greatest
+// to least. This runs in O(n log k). ( Chainable )
+func TopK[T any](c *Collection[T], k int, less func(a, b T) bool) *Collection[T] {
+	return selectK(c, k, less)
+}
+
+// BottomK returns a new collection containing the `k` least items of the
+// specified collection as defined by the less function, ordered from least to
+// greatest. This runs in O(n log k). ( Chainable )
+func BottomK[T any](c *Collection[T], k int, less func(a, b T) bool) *Collection[T] {
+	return selectK(c, k, func(a, b T) bool { return less(b, a) })
+}
+
+// selectK keeps the `k` greatest items, as defined by less, in a bounded heap
+// whose top is the least of the items retained so far.
+func selectK[T any](c *Collection[T], k int, less func(a, b T) bool) *Collection[T] {
+	if k <= 0 {
+		return New[T]()
+	}
+
+	h := &binaryHeap[T]{less: less}
+	for _, item := range c.snapshot() {
+		if h.Len() < k {
+			heap.Push(h, item)
+			continue
+		}
+
+		if less(h.items[0], item) {
+			h.items[0] = item
+			heap.Fix(h, 0)
+		}
+	}
+
+	sort.Slice(h.items, func(i, j int) bool {
+		return less(h.items[j], h.items[i])
+	})
+
+	return New(h.items...)
+}
+
+// binaryHeap adapts a slice of items to heap.Interface.
+type binaryHeap[T any] struct {
+	items []T
+	less  func(a, b T) bool
+}
+
+func (h *binaryHeap[T]) Len() int           { return len(h.items) }
+func (h *binaryHeap[T]) Less(i, j int) bool { return h.less(h.items[i], h.items[j]) }
+func (h *binaryHeap[T]) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *binaryHeap[T]) Push(x any)         { h.items = append(h.items, x.(T)) }
+
+func (h *binaryHeap[T]) Pop() any {
+	var zero T
+
+	n := len(h.items) - 1
+	out := h.items[n]
+	h.items[n] = zero
+	h.items = h.items[:n]
+
+	return out
+}
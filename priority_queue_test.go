@@ -0,0 +1,82 @@
+package collection_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wilhelm-murdoch/go-collection"
+)
+
+func lessInt(a, b int) bool { return a < b }
+
+func TestPriorityQueuePushPop(t *testing.T) {
+	q := collection.NewPriorityQueue(lessInt, 5, 2, 8)
+	assert.Equal(t, 5, q.Push(1, 9), "The queue's length should include the pushed items.")
+
+	top, found := q.Peek()
+	assert.True(t, found)
+	assert.Equal(t, 1, top, "Peek should return the least item.")
+
+	var out []int
+	for !q.IsEmpty() {
+		item, _ := q.Pop()
+		out = append(out, item)
+	}
+
+	assert.Equal(t, []int{1, 2, 5, 8, 9}, out, "Items should be popped in priority order.")
+
+	_, found = q.Pop()
+	assert.False(t, found, "Popping an empty queue should not find an item.")
+}
+
+func TestPriorityQueueUpdate(t *testing.T) {
+	type Task struct {
+		Name     string
+		Priority int
+	}
+
+	q := collection.NewPriorityQueue(func(a, b *Task) bool {
+		return a.Priority < b.Priority
+	}, &Task{"a", 1}, &Task{"b", 2}, &Task{"c", 3})
+
+	for i, task := range q.Items() {
+		if task.Name == "c" {
+			task.Priority = 0
+			assert.True(t, q.Update(i))
+		}
+	}
+
+	top, _ := q.Peek()
+	assert.Equal(t, "c", top.Name, "An updated item should move to its new position.")
+	assert.False(t, q.Update(10), "Updating an out of range index should fail.")
+}
+
+func TestPriorityQueueRemove(t *testing.T) {
+	q := collection.NewPriorityQueue(lessInt, 4, 1, 3, 2)
+
+	index := -1
+	for i, item := range q.Items() {
+		if item == 3 {
+			index = i
+		}
+	}
+
+	item, found := q.Remove(index)
+	assert.True(t, found)
+	assert.Equal(t, 3, item)
+	assert.Equal(t, []int{1, 2, 4}, q.Collection().Items(), "The removed item should no longer be queued.")
+	assert.Equal(t, 3, q.Length(), "Draining into a collection should not modify the queue.")
+
+	_, found = q.Remove(-1)
+	assert.False(t, found)
+}
+
+func TestTopK(t *testing.T) {
+	c := collection.New(7, 3, 9, 1, 5, 8, 2)
+
+	assert.Equal(t, []int{9, 8, 7}, collection.TopK(c, 3, lessInt).Items())
+	assert.Equal(t, []int{1, 2, 3}, collection.BottomK(c, 3, lessInt).Items())
+	assert.Equal(t, 7, collection.TopK(c, 10, lessInt).Length(), "k larger than the collection should return every item.")
+	assert.True(t, collection.TopK(c, 0, lessInt).IsEmpty())
+	assert.Equal(t, []int{7, 3, 9, 1, 5, 8, 2}, c.Items(), "The source collection should not be modified.")
+}
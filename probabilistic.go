@@ -0,0 +1,320 @@
+package collection
+
+import (
+	"errors"
+	"hash/fnv"
+	"math"
+	"math/bits"
+)
+
+// ErrIncompatibleSketch is returned when merging probabilistic structures
+// which were created with different parameters.
+var ErrIncompatibleSketch = errors.New("collection: cannot merge sketches with different parameters")
+
+// hashKey returns a well mixed 64-bit hash of the specified key. FNV-1a is
+// stable across processes, which keeps sketches built on different shards
+// mergeable, and the splitmix64 finaliser spreads its entropy over every bit.
+func hashKey(key string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+
+	return x
+}
+
+// indexes calls the specified function with `k` indexes below `m` derived from
+// a single hash, using the double hashing scheme of Kirsch and Mitzenmacher.
+func indexes(hash uint64, k, m int, f func(i int, index int)) {
+	h1, h2 := hash&0xffffffff, hash>>32|1
+	for i := 0; i < k; i++ {
+		f(i, int((h1+uint64(i)*h2)%uint64(m)))
+	}
+}
+
+// BloomFilter is a space efficient set which can report false positives, at a
+// configurable rate, but never false negatives. Items are identified by the
+// string returned by a key function.
+type BloomFilter[T any] struct {
+	bits   []uint64
+	m, k   int
+	key    func(T) string
+	length int
+}
+
+// NewBloomFilter returns a new Bloom filter sized to hold `expected` items with
+// a false positive rate of at most `rate`, which must be between 0 and 1.
+// Filters must be created with the same parameters to be merged.
+func NewBloomFilter[T any](expected int, rate float64, key func(T) string) *BloomFilter[T] {
+	if expected < 1 {
+		expected = 1
+	}
+
+	if rate <= 0 || rate >= 1 {
+		panic("collection: bloom filter false positive rate must be between 0 and 1")
+	}
+
+	m := int(math.Ceil(-float64(expected) * math.Log(rate) / (math.Ln2 * math.Ln2)))
+	k := int(math.Round(float64(m) / float64(expected) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+
+	return &BloomFilter[T]{
+		bits: make([]uint64, (m+63)/64),
+		m:    m,
+		k:    k,
+		key:  key,
+	}
+}
+
+// BloomFilterFrom returns a new Bloom filter containing the items of the
+// specified collection, sized like NewBloomFilter. Shards which are merged
+// later must share the same `expected` and `rate`, whatever their own size.
+func BloomFilterFrom[T any](c *Collection[T], expected int, rate float64, key func(T) string) *BloomFilter[T] {
+	return NewBloomFilter(expected, rate, key).Add(c.snapshot()...)
+}
+
+// Add adds the specified items to the current filter. ( Chainable )
+func (b *BloomFilter[T]) Add(items ...T) *BloomFilter[T] {
+	for _, item := range items {
+		indexes(hashKey(b.key(item)), b.k, b.m, func(_ int, index int) {
+			b.bits[index/64] |= 1 << (index % 64)
+		})
+		b.length++
+	}
+
+	return b
+}
+
+// Contains returns true if the specified item may have been added to the
+// current filter, and false if it definitely has not.
+func (b *BloomFilter[T]) Contains(item T) bool {
+	found := true
+	indexes(hashKey(b.key(item)), b.k, b.m, func(_ int, index int) {
+		if b.bits[index/64]&(1<<(index%64)) == 0 {
+			found = false
+		}
+	})
+
+	return found
+}
+
+// Length returns the number of items added to the current filter, including
+// duplicates.
+func (b *BloomFilter[T]) Length() int {
+	return b.length
+}
+
+// Merge adds every item of the specified filter to the current one. Both must
+// have been created with the same parameters.
+func (b *BloomFilter[T]) Merge(other *BloomFilter[T]) error {
+	if b.m != other.m || b.k != other.k {
+		return ErrIncompatibleSketch
+	}
+
+	for i, word := range other.bits {
+		b.bits[i] |= word
+	}
+	b.length += other.length
+
+	return nil
+}
+
+// HyperLogLog estimates the number of distinct items in a stream using a fixed
+// amount of memory. Items are identified by the string returned by a key
+// function.
+type HyperLogLog[T any] struct {
+	registers []uint8
+	precision uint8
+	key       func(T) string
+}
+
+// NewHyperLogLog returns a new HyperLogLog using 2^precision registers, where
+// precision is clamped between 4 and 18. The standard error of its estimates is
+// roughly 1.04/sqrt(2^precision), so a precision of 14 uses 16KiB for about
+// 0.8% error.
+func NewHyperLogLog[T any](precision uint8, key func(T) string) *HyperLogLog[T] {
+	if precision < 4 {
+		precision = 4
+	}
+
+	if precision > 18 {
+		precision = 18
+	}
+
+	return &HyperLogLog[T]{
+		registers: make([]uint8, 1<<precision),
+		precision: precision,
+		key:       key,
+	}
+}
+
+// HyperLogLogFrom returns a new HyperLogLog containing the items of the
+// specified collection.
+func HyperLogLogFrom[T any](c *Collection[T], precision uint8, key func(T) string) *HyperLogLog[T] {
+	return NewHyperLogLog(precision, key).Add(c.snapshot()...)
+}
+
+// Add adds the specified items to the current estimate. ( Chainable )
+func (h *HyperLogLog[T]) Add(items ...T) *HyperLogLog[T] {
+	for _, item := range items {
+		hash := hashKey(h.key(item))
+
+		register := hash >> (64 - h.precision)
+		rank := uint8(bits.LeadingZeros64(hash<<h.precision|1<<(h.precision-1))) + 1
+
+		if rank > h.registers[register] {
+			h.registers[register] = rank
+		}
+	}
+
+	return h
+}
+
+// Count returns the estimated number of distinct items added so far.
+func (h *HyperLogLog[T]) Count() uint64 {
+	m := float64(len(h.registers))
+
+	var (
+		sum   float64
+		zeros int
+	)
+
+	for _, register := range h.registers {
+		sum += 1 / float64(uint64(1)<<register)
+		if register == 0 {
+			zeros++
+		}
+	}
+
+	var alpha float64
+	switch len(h.registers) {
+	case 16:
+		alpha = 0.673
+	case 32:
+		alpha = 0.697
+	case 64:
+		alpha = 0.709
+	default:
+		alpha = 0.7213 / (1 + 1.079/m)
+	}
+
+	estimate := alpha * m * m / sum
+
+	// Small cardinalities are estimated more accurately by linear counting.
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+
+	return uint64(estimate + 0.5)
+}
+
+// Merge combines the specified HyperLogLog into the current one, so that it
+// estimates the distinct items of both. Both must use the same precision.
+func (h *HyperLogLog[T]) Merge(other *HyperLogLog[T]) error {
+	if h.precision != other.precision {
+		return ErrIncompatibleSketch
+	}
+
+	for i, register := range other.registers {
+		if register > h.registers[i] {
+			h.registers[i] = register
+		}
+	}
+
+	return nil
+}
+
+// CountMinSketch estimates how often items occur in a stream using a fixed
+// amount of memory. Estimates never undercount, and overcount by at most
+// `epsilon` times the total count with probability `1 - delta`. Items are
+// identified by the string returned by a key function.
+type CountMinSketch[T any] struct {
+	counts       [][]uint64
+	width, depth int
+	total        uint64
+	key          func(T) string
+}
+
+// NewCountMinSketch returns a new Count-Min sketch with the specified error
+// bounds, both of which must be between 0 and 1.
+func NewCountMinSketch[T any](epsilon, delta float64, key func(T) string) *CountMinSketch[T] {
+	if epsilon <= 0 || epsilon >= 1 || delta <= 0 || delta >= 1 {
+		panic("collection: count-min sketch error bounds must be between 0 and 1")
+	}
+
+	width := int(math.Ceil(math.E / epsilon))
+	depth := int(math.Ceil(math.Log(1 / delta)))
+
+	counts := make([][]uint64, depth)
+	for i := range counts {
+		counts[i] = make([]uint64, width)
+	}
+
+	return &CountMinSketch[T]{counts: counts, width: width, depth: depth, key: key}
+}
+
+// CountMinSketchFrom returns a new Count-Min sketch containing the items of
+// the specified collection.
+func CountMinSketchFrom[T any](c *Collection[T], epsilon, delta float64, key func(T) string) *CountMinSketch[T] {
+	return NewCountMinSketch(epsilon, delta, key).Add(c.snapshot()...)
+}
+
+// Add counts one occurrence of each of the specified items. ( Chainable )
+func (s *CountMinSketch[T]) Add(items ...T) *CountMinSketch[T] {
+	for _, item := range items {
+		s.AddCount(item, 1)
+	}
+
+	return s
+}
+
+// AddCount counts `n` occurrences of the specified item. ( Chainable )
+func (s *CountMinSketch[T]) AddCount(item T, n uint64) *CountMinSketch[T] {
+	indexes(hashKey(s.key(item)), s.depth, s.width, func(row int, index int) {
+		s.counts[row][index] += n
+	})
+	s.total += n
+
+	return s
+}
+
+// Estimate returns the estimated number of occurrences of the specified item.
+func (s *CountMinSketch[T]) Estimate(item T) uint64 {
+	out := uint64(math.MaxUint64)
+	indexes(hashKey(s.key(item)), s.depth, s.width, func(row int, index int) {
+		if s.counts[row][index] < out {
+			out = s.counts[row][index]
+		}
+	})
+
+	return out
+}
+
+// Total returns the number of occurrences counted so far.
+func (s *CountMinSketch[T]) Total() uint64 {
+	return s.total
+}
+
+// Merge adds the counts of the specified sketch to the current one. Both must
+// have been created with the same error bounds.
+func (s *CountMinSketch[T]) Merge(other *CountMinSketch[T]) error {
+	if s.width != other.width || s.depth != other.depth {
+		return ErrIncompatibleSketch
+	}
+
+	for row := range other.counts {
+		for i, count := range other.counts[row] {
+			s.counts[row][i] += count
+		}
+	}
+	s.total += other.total
+
+	return nil
+}
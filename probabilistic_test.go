@@ -0,0 +1,104 @@
+package collection_test
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wilhelm-murdoch/go-collection"
+)
+
+func returnRange(from, to int) *collection.Collection[int] {
+	c := collection.New[int]()
+	for i := from; i < to; i++ {
+		c.Push(i)
+	}
+
+	return c
+}
+
+func TestBloomFilter(t *testing.T) {
+	filter := collection.BloomFilterFrom(returnRange(0, 10000), 10000, 0.01, strconv.Itoa)
+
+	for i := 0; i < 10000; i++ {
+		assert.True(t, filter.Contains(i), "Bloom filters should never report false negatives.")
+	}
+
+	var falsePositives int
+	for i := 10000; i < 20000; i++ {
+		if filter.Contains(i) {
+			falsePositives++
+		}
+	}
+	assert.Less(t, falsePositives, 200, "The false positive rate should stay close to the configured rate.")
+	assert.Equal(t, 10000, filter.Length())
+
+	assert.Panics(t, func() { collection.NewBloomFilter(10, 1.5, strconv.Itoa) })
+}
+
+func TestBloomFilterMerge(t *testing.T) {
+	left := collection.NewBloomFilter(1000, 0.01, strconv.Itoa).Add(1, 2, 3)
+	right := collection.NewBloomFilter(1000, 0.01, strconv.Itoa).Add(4, 5, 6)
+
+	assert.False(t, left.Contains(5))
+	assert.Nil(t, left.Merge(right))
+	assert.True(t, left.Contains(5))
+	assert.Equal(t, 6, left.Length())
+
+	err := left.Merge(collection.NewBloomFilter(10, 0.5, strconv.Itoa))
+	assert.True(t, errors.Is(err, collection.ErrIncompatibleSketch))
+
+	small := collection.BloomFilterFrom(returnRange(0, 10), 1000, 0.01, strconv.Itoa)
+	large := collection.BloomFilterFrom(returnRange(10, 500), 1000, 0.01, strconv.Itoa)
+	assert.Nil(t, small.Merge(large), "Shards of different sizes sharing parameters should merge.")
+	assert.True(t, small.Contains(250))
+}
+
+func TestHyperLogLog(t *testing.T) {
+	for _, n := range []int{10, 1000, 100000} {
+		hll := collection.NewHyperLogLog(14, strconv.Itoa)
+		for i := 0; i < n; i++ {
+			hll.Add(i, i)
+		}
+
+		assert.InEpsilon(t, n, hll.Count(), 0.03, "Estimates should be within a few percent for %d items.", n)
+	}
+
+	left := collection.HyperLogLogFrom(returnRange(0, 6000), 12, strconv.Itoa)
+	right := collection.HyperLogLogFrom(returnRange(4000, 10000), 12, strconv.Itoa)
+
+	assert.Nil(t, left.Merge(right))
+	assert.InEpsilon(t, 10000, left.Count(), 0.05, "Merged estimates should count shared items once.")
+
+	err := left.Merge(collection.NewHyperLogLog(10, strconv.Itoa))
+	assert.True(t, errors.Is(err, collection.ErrIncompatibleSketch))
+}
+
+func TestCountMinSketch(t *testing.T) {
+	words := collection.New[string]()
+	for i := 0; i < 1000; i++ {
+		words.Push("word" + strconv.Itoa(i%100))
+	}
+	words.Push("rare")
+
+	identity := func(s string) string { return s }
+
+	sketch := collection.CountMinSketchFrom(words, 0.001, 0.01, identity)
+	assert.Equal(t, uint64(1001), sketch.Total())
+
+	for i := 0; i < 100; i++ {
+		estimate := sketch.Estimate("word" + strconv.Itoa(i))
+		assert.GreaterOrEqual(t, estimate, uint64(10), "Count-Min sketches should never undercount.")
+		assert.LessOrEqual(t, float64(estimate), 10+math.Ceil(0.001*1001))
+	}
+
+	other := collection.NewCountMinSketch(0.001, 0.01, identity).AddCount("rare", 4)
+	assert.Nil(t, sketch.Merge(other))
+	assert.Equal(t, uint64(5), sketch.Estimate("rare"))
+	assert.Equal(t, uint64(1005), sketch.Total())
+
+	err := sketch.Merge(collection.NewCountMinSketch(0.1, 0.01, identity))
+	assert.True(t, errors.Is(err, collection.ErrIncompatibleSketch))
+}
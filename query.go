@@ -0,0 +1,353 @@
+package collection
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Query is a lazily evaluated, declarative query over a collection of items of
+// type T. Each builder method returns a new query, leaving the original intact,
+// and no work is done until a terminal method such as `Collection`, `Items` or
+// `Count` is called.
+type Query[T any] struct {
+	run  func() []T
+	plan []string
+
+	// unordered and orders are set when the query ends in an OrderBy stage so
+	// that ThenBy and ThenByDescending can extend it.
+	unordered *Query[T]
+	orders    []ordering[T]
+}
+
+type ordering[T any] struct {
+	less       func(a, b T) bool
+	descending bool
+}
+
+// Group is a set of items sharing the same key, as produced by GroupBy.
+type Group[K comparable, T any] struct {
+	Key   K
+	Items *Collection[T]
+}
+
+// Query returns a new query which reads from the current collection when it is
+// evaluated. ( Chainable )
+func (c *Collection[T]) Query() *Query[T] {
+	return &Query[T]{
+		run: func() []T {
+			defer c.rlock()()
+
+			return append([]T(nil), c.items...)
+		},
+		plan: []string{"Scan collection"},
+	}
+}
+
+func (q *Query[T]) then(step string, f func([]T) []T) *Query[T] {
+	return &Query[T]{
+		run: func() []T {
+			return f(q.run())
+		},
+		plan: append(append([]string(nil), q.plan...), step),
+	}
+}
+
+// Where returns a new query retaining only the items which satisfy the
+// specified predicate. ( Chainable )
+func (q *Query[T]) Where(f func(T) bool) *Query[T] {
+	return q.then("Where", func(items []T) []T {
+		return filterItems(items, f)
+	})
+}
+
+// Having returns a new query retaining only the items which satisfy the
+// specified predicate. It behaves exactly like Where and is intended to filter
+// the groups produced by GroupBy. ( Chainable )
+func (q *Query[T]) Having(f func(T) bool) *Query[T] {
+	return q.then("Having", func(items []T) []T {
+		return filterItems(items, f)
+	})
+}
+
+func filterItems[T any](items []T, f func(T) bool) []T {
+	out := items[:0]
+	for _, item := range items {
+		if f(item) {
+			out = append(out, item)
+		}
+	}
+
+	return out
+}
+
+// OrderBy returns a new query which sorts items in ascending order as defined
+// by the specified less function. The sort is stable. ( Chainable )
+func (q *Query[T]) OrderBy(less func(a, b T) bool) *Query[T] {
+	return q.order(q, []ordering[T]{{less, false}})
+}
+
+// OrderByDescending returns a new query which sorts items in descending order
+// as defined by the specified less function. The sort is stable. ( Chainable )
+func (q *Query[T]) OrderByDescending(less func(a, b T) bool) *Query[T] {
+	return q.order(q, []ordering[T]{{less, true}})
+}
+
+// ThenBy returns a new query which sorts items that compare equal in the
+// preceding OrderBy in ascending order as defined by the specified less
+// function. Without a preceding OrderBy it behaves like OrderBy. ( Chainable )
+func (q *Query[T]) ThenBy(less func(a, b T) bool) *Query[T] {
+	return q.thenOrder(ordering[T]{less, false})
+}
+
+// ThenByDescending returns a new query which sorts items that compare equal in
+// the preceding OrderBy in descending order as defined by the specified less
+// function. Without a preceding OrderBy it behaves like OrderByDescending.
+// ( Chainable )
+func (q *Query[T]) ThenByDescending(less func(a, b T) bool) *Query[T] {
+	return q.thenOrder(ordering[T]{less, true})
+}
+
+func (q *Query[T]) thenOrder(o ordering[T]) *Query[T] {
+	if q.unordered == nil {
+		return q.order(q, []ordering[T]{o})
+	}
+
+	return q.order(q.unordered, append(append([]ordering[T](nil), q.orders...), o))
+}
+
+func (q *Query[T]) order(source *Query[T], orders []ordering[T]) *Query[T] {
+	names := make([]string, len(orders))
+	for i, o := range orders {
+		switch {
+		case i == 0 && o.descending:
+			names[i] = "OrderByDescending"
+		case i == 0:
+			names[i] = "OrderBy"
+		case o.descending:
+			names[i] = "ThenByDescending"
+		default:
+			names[i] = "ThenBy"
+		}
+	}
+
+	out := source.then(strings.Join(names, ", "), func(items []T) []T {
+		sort.SliceStable(items, func(i, j int) bool {
+			for _, o := range orders {
+				a, b := items[i], items[j]
+				if o.descending {
+					a, b = b, a
+				}
+
+				if o.less(a, b) {
+					return true
+				}
+
+				if o.less(b, a) {
+					return false
+				}
+			}
+
+			return false
+		})
+
+		return items
+	})
+	out.unordered, out.orders = source, orders
+
+	return out
+}
+
+// Distinct returns a new query which drops items equal to an earlier item. This
+// makes use of `reflect.DeepEqual`; use DistinctBy for large result sets.
+// ( Chainable )
+func (q *Query[T]) Distinct() *Query[T] {
+	return q.then("Distinct", func(items []T) []T {
+		var out []T
+		for _, item := range items {
+			found := false
+			for _, seen := range out {
+				if reflect.DeepEqual(item, seen) {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				out = append(out, item)
+			}
+		}
+
+		return out
+	})
+}
+
+// DistinctBy returns a new query which drops items whose key, as returned by
+// the specified function, matches that of an earlier item. Keys must be
+// comparable. ( Chainable )
+func (q *Query[T]) DistinctBy(key func(T) any) *Query[T] {
+	return q.then("DistinctBy", func(items []T) []T {
+		seen := make(map[any]bool)
+		return filterItems(items, func(item T) bool {
+			k := key(item)
+			if seen[k] {
+				return false
+			}
+			seen[k] = true
+			return true
+		})
+	})
+}
+
+// Offset returns a new query which skips the first `n` items. ( Chainable )
+func (q *Query[T]) Offset(n int) *Query[T] {
+	return q.then(fmt.Sprintf("Offset %d", n), func(items []T) []T {
+		switch {
+		case n <= 0:
+			return items
+		case n > len(items):
+			return items[len(items):]
+		}
+
+		return items[n:]
+	})
+}
+
+// Limit returns a new query which yields at most `n` items. ( Chainable )
+func (q *Query[T]) Limit(n int) *Query[T] {
+	return q.then(fmt.Sprintf("Limit %d", n), func(items []T) []T {
+		switch {
+		case n <= 0:
+			return items[:0]
+		case n < len(items):
+			return items[:n]
+		}
+
+		return items
+	})
+}
+
+// Explain returns a human readable description of the steps the current query
+// will perform when evaluated, in order.
+func (q *Query[T]) Explain() string {
+	var b strings.Builder
+	for i, step := range q.plan {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, step)
+	}
+
+	return b.String()
+}
+
+// Items evaluates the current query and returns the resulting items.
+func (q *Query[T]) Items() []T {
+	return q.run()
+}
+
+// Collection evaluates the current query and returns a new collection of the
+// resulting items. ( Chainable )
+func (q *Query[T]) Collection() *Collection[T] {
+	return New(q.run()...)
+}
+
+// First evaluates the current query and returns the first resulting item along
+// with a boolean value stating whether or not an item could be found.
+func (q *Query[T]) First() (T, bool) {
+	return q.Collection().AtFirst()
+}
+
+// Count evaluates the current query and returns the number of resulting items.
+func (q *Query[T]) Count() int {
+	return len(q.run())
+}
+
+// Sum evaluates the current query and returns the sum of the specified value
+// for each resulting item.
+func (q *Query[T]) Sum(f func(T) float64) float64 {
+	return sumItems(q.run(), f)
+}
+
+// Avg evaluates the current query and returns the mean of the specified value
+// for each resulting item, or 0 if there are none.
+func (q *Query[T]) Avg(f func(T) float64) float64 {
+	return avgItems(q.run(), f)
+}
+
+// Count returns the number of items in the current group.
+func (g Group[K, T]) Count() int {
+	return g.Items.Length()
+}
+
+// Sum returns the sum of the specified value for each item in the current
+// group.
+func (g Group[K, T]) Sum(f func(T) float64) float64 {
+	return sumItems(g.Items.items, f)
+}
+
+// Avg returns the mean of the specified value for each item in the current
+// group, or 0 if the group is empty.
+func (g Group[K, T]) Avg(f func(T) float64) float64 {
+	return avgItems(g.Items.items, f)
+}
+
+func sumItems[T any](items []T, f func(T) float64) (out float64) {
+	for _, item := range items {
+		out += f(item)
+	}
+
+	return out
+}
+
+func avgItems[T any](items []T, f func(T) float64) float64 {
+	if len(items) == 0 {
+		return 0
+	}
+
+	return sumItems(items, f) / float64(len(items))
+}
+
+// Select returns a new query which projects each item of the specified query
+// into a value of type U. ( Chainable )
+func Select[T, U any](q *Query[T], f func(T) U) *Query[U] {
+	return &Query[U]{
+		run: func() []U {
+			items := q.run()
+
+			out := make([]U, len(items))
+			for i, item := range items {
+				out[i] = f(item)
+			}
+
+			return out
+		},
+		plan: append(append([]string(nil), q.plan...), "Select"),
+	}
+}
+
+// GroupBy returns a new query which collects the items of the specified query
+// into groups sharing the same key. Groups are ordered by the first appearance
+// of their key. ( Chainable )
+func GroupBy[T any, K comparable](q *Query[T], key func(T) K) *Query[Group[K, T]] {
+	return &Query[Group[K, T]]{
+		run: func() []Group[K, T] {
+			var out []Group[K, T]
+
+			positions := make(map[K]int)
+			for _, item := range q.run() {
+				k := key(item)
+
+				i, found := positions[k]
+				if !found {
+					i = len(out)
+					positions[k] = i
+					out = append(out, Group[K, T]{k, New[T]()})
+				}
+
+				out[i].Items.Push(item)
+			}
+
+			return out
+		},
+		plan: append(append([]string(nil), q.plan...), "GroupBy"),
+	}
+}
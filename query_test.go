@@ -0,0 +1,118 @@
+package collection_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wilhelm-murdoch/go-collection"
+)
+
+type Sale struct {
+	Region string
+	Rep    string
+	Amount float64
+}
+
+func returnSales() *collection.Collection[Sale] {
+	return collection.New(
+		Sale{"north", "luke", 100},
+		Sale{"south", "rob", 250},
+		Sale{"north", "peter", 300},
+		Sale{"east", "josh", 50},
+		Sale{"south", "wilhelm", 150},
+		Sale{"north", "luke", 200},
+	)
+}
+
+func TestQueryWhereOrderLimit(t *testing.T) {
+	q := returnSales().Query().
+		Where(func(s Sale) bool { return s.Amount >= 100 }).
+		OrderBy(func(a, b Sale) bool { return a.Region < b.Region }).
+		ThenByDescending(func(a, b Sale) bool { return a.Amount < b.Amount }).
+		Offset(1).
+		Limit(3)
+
+	reps := collection.Select(q, func(s Sale) string { return s.Rep }).Items()
+	assert.Equal(t, []string{"luke", "luke", "rob"}, reps)
+}
+
+func TestQueryIsLazy(t *testing.T) {
+	c := collection.New(1, 2, 3)
+	calls := 0
+
+	q := c.Query().Where(func(i int) bool {
+		calls++
+		return i > 1
+	})
+	assert.Equal(t, 0, calls, "Building a query should not evaluate it.")
+
+	c.Push(4)
+	assert.Equal(t, []int{2, 3, 4}, q.Items(), "Queries should read the collection when evaluated.")
+	assert.Equal(t, 4, calls)
+	assert.Equal(t, []int{1, 2, 3, 4}, c.Items(), "Queries should not modify the source collection.")
+}
+
+func TestQueryGroupBy(t *testing.T) {
+	type Row struct {
+		Region string
+		Count  int
+		Total  float64
+		Avg    float64
+	}
+
+	amount := func(s Sale) float64 { return s.Amount }
+
+	groups := collection.GroupBy(returnSales().Query(), func(s Sale) string { return s.Region }).
+		Having(func(g collection.Group[string, Sale]) bool { return g.Count() > 1 })
+
+	rows := collection.Select(groups, func(g collection.Group[string, Sale]) Row {
+		return Row{g.Key, g.Count(), g.Sum(amount), g.Avg(amount)}
+	}).OrderByDescending(func(a, b Row) bool { return a.Total < b.Total }).Items()
+
+	assert.Equal(t, []Row{
+		{"north", 3, 600, 200},
+		{"south", 2, 400, 200},
+	}, rows)
+}
+
+func TestQueryDistinct(t *testing.T) {
+	c := collection.New(3, 1, 3, 2, 1)
+
+	assert.Equal(t, []int{3, 1, 2}, c.Query().Distinct().Items())
+
+	reps := returnSales().Query().DistinctBy(func(s Sale) any { return s.Rep }).Count()
+	assert.Equal(t, 5, reps)
+}
+
+func TestQueryAggregates(t *testing.T) {
+	q := returnSales().Query().Where(func(s Sale) bool { return s.Region == "north" })
+	amount := func(s Sale) float64 { return s.Amount }
+
+	assert.Equal(t, 3, q.Count())
+	assert.Equal(t, 600.0, q.Sum(amount))
+	assert.Equal(t, 200.0, q.Avg(amount))
+	assert.Equal(t, 0.0, q.Limit(0).Avg(amount), "The mean of no items should be 0.")
+
+	first, found := q.First()
+	assert.True(t, found)
+	assert.Equal(t, "luke", first.Rep)
+}
+
+func TestQueryOffsetLimitBounds(t *testing.T) {
+	q := collection.New(1, 2, 3).Query()
+
+	assert.Empty(t, q.Offset(5).Items())
+	assert.Equal(t, []int{1, 2, 3}, q.Offset(-1).Limit(10).Items())
+	assert.Empty(t, q.Limit(-1).Items())
+}
+
+func TestQueryExplain(t *testing.T) {
+	q := returnSales().Query().
+		Where(func(s Sale) bool { return s.Amount > 0 }).
+		OrderBy(func(a, b Sale) bool { return a.Region < b.Region }).
+		ThenBy(func(a, b Sale) bool { return a.Rep < b.Rep }).
+		Limit(2)
+
+	expected := "1. Scan collection\n2. Where\n3. OrderBy, ThenBy\n4. Limit 2\n"
+	assert.Equal(t, expected, q.Explain())
+}
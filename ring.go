@@ -0,0 +1,200 @@
+package collection
+
+import "sync"
+
+// RingMode describes how a ring behaves once it has reached capacity.
+type RingMode int
+
+const (
+	// RingOverwrite discards the oldest item in the ring to make room for a new
+	// one.
+	RingOverwrite RingMode = iota
+	// RingBlock causes producers to wait until a consumer has made room.
+	RingBlock
+	// RingReject refuses new items until a consumer has made room.
+	RingReject
+)
+
+// Ring is a fixed-capacity buffer of items of type T which is safe for
+// concurrent use by multiple producers and consumers. Items are kept in
+// insertion order, oldest to newest.
+type Ring[T any] struct {
+	mu       sync.Mutex
+	notFull  *sync.Cond
+	notEmpty *sync.Cond
+	items    []T
+	head     int
+	length   int
+	mode     RingMode
+	closed   bool
+}
+
+// NewRing returns a new ring of type T which can hold up to `capacity` items
+// and behaves according to `mode` once full. This function panics if the
+// specified capacity is less than 1.
+func NewRing[T any](capacity int, mode RingMode) *Ring[T] {
+	if capacity < 1 {
+		panic("collection: ring capacity must be greater than zero")
+	}
+
+	r := &Ring[T]{
+		items: make([]T, capacity),
+		mode:  mode,
+	}
+	r.notFull = sync.NewCond(&r.mu)
+	r.notEmpty = sync.NewCond(&r.mu)
+
+	return r
+}
+
+// Push appends an item to the end of the current ring. If the ring is full, the
+// ring's mode determines whether the oldest item is overwritten, the call
+// blocks until room is made or the call is rejected. Returns false if the item
+// was rejected or the ring has been closed.
+func (r *Ring[T]) Push(item T) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for !r.closed && r.length == len(r.items) {
+		switch r.mode {
+		case RingOverwrite:
+			var zero T
+			r.items[r.head] = zero
+			r.head = (r.head + 1) % len(r.items)
+			r.length--
+		case RingBlock:
+			r.notFull.Wait()
+		default:
+			return false
+		}
+	}
+
+	if r.closed {
+		return false
+	}
+
+	r.items[(r.head+r.length)%len(r.items)] = item
+	r.length++
+	r.notEmpty.Signal()
+
+	return true
+}
+
+// Shift removes the oldest item from the current ring, then returns that item
+// along with a boolean value stating whether or not an item could be found.
+// This method never blocks.
+func (r *Ring[T]) Shift() (out T, found bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.length == 0 {
+		return
+	}
+
+	return r.shift(), true
+}
+
+// Take removes the oldest item from the current ring, then returns that item.
+// If the ring is empty, Take blocks until an item is pushed or the ring is
+// closed, in which case the boolean value returned is false.
+func (r *Ring[T]) Take() (out T, found bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for r.length == 0 {
+		if r.closed {
+			return
+		}
+		r.notEmpty.Wait()
+	}
+
+	return r.shift(), true
+}
+
+func (r *Ring[T]) shift() T {
+	var zero T
+
+	out := r.items[r.head]
+	r.items[r.head] = zero
+	r.head = (r.head + 1) % len(r.items)
+	r.length--
+	r.notFull.Signal()
+
+	return out
+}
+
+// Close marks the current ring as closed. Subsequent pushes are rejected and
+// any blocked producers or consumers are released. Items already in the ring
+// can still be consumed.
+func (r *Ring[T]) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.closed = true
+	r.notFull.Broadcast()
+	r.notEmpty.Broadcast()
+}
+
+// Length returns number of items currently held by the ring.
+func (r *Ring[T]) Length() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.length
+}
+
+// Capacity returns the maximum number of items the ring can hold.
+func (r *Ring[T]) Capacity() int {
+	return len(r.items)
+}
+
+// IsEmpty returns a boolean value describing the empty state of the current
+// ring.
+func (r *Ring[T]) IsEmpty() bool {
+	return r.Length() == 0
+}
+
+// IsFull returns a boolean value stating whether or not the current ring has
+// reached its capacity.
+func (r *Ring[T]) IsFull() bool {
+	return r.Length() == r.Capacity()
+}
+
+// Empty will reset the current ring to zero items, releasing any blocked
+// producers. ( Chainable )
+func (r *Ring[T]) Empty() *Ring[T] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var zero T
+	for i := range r.items {
+		r.items[i] = zero
+	}
+	r.head, r.length = 0, 0
+	r.notFull.Broadcast()
+
+	return r
+}
+
+// Each iterates through the current ring's items from oldest to newest and
+// executes the specified callback on each item. Iteration stops when the
+// callback returns true. The callback operates on a snapshot of the ring, so it
+// is safe to modify the ring from within it. ( Chainable )
+func (r *Ring[T]) Each(f func(int, T) bool) *Ring[T] {
+	r.Snapshot().Each(f)
+	return r
+}
+
+// Snapshot returns a new collection containing the current ring's items from
+// oldest to newest. ( Chainable )
+func (r *Ring[T]) Snapshot() *Collection[T] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	items := make([]T, r.length)
+	for i := range items {
+		items[i] = r.items[(r.head+i)%len(r.items)]
+	}
+
+	return New(items...)
+}
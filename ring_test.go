@@ -0,0 +1,102 @@
+package collection_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wilhelm-murdoch/go-collection"
+)
+
+func TestRingOverwrite(t *testing.T) {
+	r := collection.NewRing[int](3, collection.RingOverwrite)
+
+	for i := 1; i <= 5; i++ {
+		assert.True(t, r.Push(i), "An overwriting ring should always accept new items.")
+	}
+
+	assert.True(t, r.IsFull(), "The ring should be full.")
+	assert.Equal(t, []int{3, 4, 5}, r.Snapshot().Items(), "The ring should only retain the newest items.")
+
+	item, found := r.Shift()
+	assert.True(t, found)
+	assert.Equal(t, 3, item, "The oldest item should be shifted first.")
+	assert.Equal(t, 2, r.Length())
+}
+
+func TestRingReject(t *testing.T) {
+	r := collection.NewRing[string](2, collection.RingReject)
+
+	assert.True(t, r.Push("apple"))
+	assert.True(t, r.Push("orange"))
+	assert.False(t, r.Push("strawberry"), "A full rejecting ring should refuse new items.")
+	assert.Equal(t, []string{"apple", "orange"}, r.Snapshot().Items())
+
+	r.Shift()
+	assert.True(t, r.Push("strawberry"), "A rejecting ring should accept items once room is made.")
+	assert.Equal(t, []string{"orange", "strawberry"}, r.Snapshot().Items())
+}
+
+func TestRingEach(t *testing.T) {
+	r := collection.NewRing[int](4, collection.RingOverwrite)
+	for i := 0; i < 6; i++ {
+		r.Push(i)
+	}
+
+	var seen []int
+	r.Each(func(i, item int) bool {
+		seen = append(seen, item)
+		r.Push(item * 10)
+		return false
+	})
+
+	assert.Equal(t, []int{2, 3, 4, 5}, seen, "Each should iterate from oldest to newest.")
+}
+
+func TestRingEmpty(t *testing.T) {
+	r := collection.NewRing[int](2, collection.RingBlock)
+	r.Push(1)
+	r.Push(2)
+
+	assert.True(t, r.Empty().IsEmpty(), "The ring should contain zero items.")
+
+	_, found := r.Shift()
+	assert.False(t, found, "Shifting an empty ring should not find an item.")
+}
+
+func TestRingBlockingConcurrency(t *testing.T) {
+	r := collection.NewRing[int](4, collection.RingBlock)
+
+	producers, perProducer := 4, 250
+
+	var wg sync.WaitGroup
+	wg.Add(producers)
+	for p := 0; p < producers; p++ {
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				r.Push(i)
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		r.Close()
+	}()
+
+	count := 0
+	for {
+		if _, found := r.Take(); !found {
+			break
+		}
+		count++
+	}
+
+	assert.Equal(t, producers*perProducer, count, "Every pushed item should be consumed exactly once.")
+	assert.False(t, r.Push(1), "A closed ring should reject new items.")
+}
+
+func TestNewRingPanics(t *testing.T) {
+	assert.Panics(t, func() { collection.NewRing[int](0, collection.RingOverwrite) })
+}
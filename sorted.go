@@ -0,0 +1,211 @@
+package collection
+
+import "sort"
+
+// SortedCollection is a collection of items of type T which is always kept in
+// the order defined by its less function. Lookups make use of binary search
+// and run in O(log n).
+type SortedCollection[T any] struct {
+	items []T
+	less  func(a, b T) bool
+}
+
+// NewSorted returns a new sorted collection ordered by the specified less
+// function and containing the specified items. ( Chainable )
+func NewSorted[T any](less func(a, b T) bool, items ...T) *SortedCollection[T] {
+	s := &SortedCollection[T]{
+		items: append([]T(nil), items...),
+		less:  less,
+	}
+
+	sort.SliceStable(s.items, func(i, j int) bool {
+		return less(s.items[i], s.items[j])
+	})
+
+	return s
+}
+
+// Items returns the current collection's set of items in sorted order.
+func (s *SortedCollection[T]) Items() []T {
+	return s.items
+}
+
+// Length returns number of items associated with the current collection.
+func (s *SortedCollection[T]) Length() int {
+	return len(s.items)
+}
+
+// IsEmpty returns a boolean value describing the empty state of the current
+// collection.
+func (s *SortedCollection[T]) IsEmpty() bool {
+	return s.Length() == 0
+}
+
+// At attempts to return the item associated with the specified index for the
+// current collection along with a boolean value stating whether or not an item
+// could be found.
+func (s *SortedCollection[T]) At(index int) (out T, found bool) {
+	if index < 0 || index >= s.Length() {
+		return
+	}
+
+	return s.items[index], true
+}
+
+// Insert places one or more items at their sorted positions within the current
+// collection, returning the new length. Items equal to existing ones are placed
+// after them.
+func (s *SortedCollection[T]) Insert(items ...T) int {
+	for _, item := range items {
+		index := s.UpperBound(item)
+
+		var zero T
+		s.items = append(s.items, zero)
+		copy(s.items[index+1:], s.items[index:])
+		s.items[index] = item
+	}
+
+	return s.Length()
+}
+
+// Remove removes the first item equal to the specified item from the current
+// collection. Returns false if no such item could be found.
+func (s *SortedCollection[T]) Remove(item T) bool {
+	index := s.IndexOf(item)
+	if index < 0 {
+		return false
+	}
+
+	s.RemoveAt(index)
+	return true
+}
+
+// RemoveAt removes the item at the specified index from the current collection
+// and then returns that item along with a boolean value stating whether or not
+// an item could be found.
+func (s *SortedCollection[T]) RemoveAt(index int) (out T, found bool) {
+	if out, found = s.At(index); !found {
+		return
+	}
+
+	s.items = append(s.items[:index], s.items[index+1:]...)
+	return out, true
+}
+
+// IndexOf returns the index of the first item equal to the specified item, or
+// -1 if it is not present. Two items are equal when neither is less than the
+// other.
+func (s *SortedCollection[T]) IndexOf(item T) int {
+	index := s.LowerBound(item)
+	if index < s.Length() && !s.less(item, s.items[index]) {
+		return index
+	}
+
+	return -1
+}
+
+// Contains returns true if an item equal to the specified item is present in
+// the current collection.
+func (s *SortedCollection[T]) Contains(item T) bool {
+	return s.IndexOf(item) >= 0
+}
+
+// LowerBound returns the index of the first item which is not less than the
+// specified item, or the length of the collection if there is none.
+func (s *SortedCollection[T]) LowerBound(item T) int {
+	return sort.Search(s.Length(), func(i int) bool {
+		return !s.less(s.items[i], item)
+	})
+}
+
+// UpperBound returns the index of the first item which is greater than the
+// specified item, or the length of the collection if there is none.
+func (s *SortedCollection[T]) UpperBound(item T) int {
+	return sort.Search(s.Length(), func(i int) bool {
+		return s.less(item, s.items[i])
+	})
+}
+
+// Range returns a new collection containing every item between `lo` and `hi`,
+// inclusive, in sorted order. ( Chainable )
+func (s *SortedCollection[T]) Range(lo, hi T) *Collection[T] {
+	from, to := s.LowerBound(lo), s.UpperBound(hi)
+	if from > to {
+		from = to
+	}
+
+	return New(append([]T(nil), s.items[from:to]...)...)
+}
+
+// Floor returns the greatest item which is less than or equal to the specified
+// item along with a boolean value stating whether or not an item could be
+// found.
+func (s *SortedCollection[T]) Floor(item T) (T, bool) {
+	return s.At(s.UpperBound(item) - 1)
+}
+
+// Ceiling returns the least item which is greater than or equal to the
+// specified item along with a boolean value stating whether or not an item
+// could be found.
+func (s *SortedCollection[T]) Ceiling(item T) (T, bool) {
+	return s.At(s.LowerBound(item))
+}
+
+// Rank returns the number of items in the current collection which are less
+// than the specified item.
+func (s *SortedCollection[T]) Rank(item T) int {
+	return s.LowerBound(item)
+}
+
+// Select returns the item with the specified rank, ie; the item that would be
+// found at index `k` in sorted order, along with a boolean value stating
+// whether or not an item could be found.
+func (s *SortedCollection[T]) Select(k int) (T, bool) {
+	return s.At(k)
+}
+
+// Merge returns a new sorted collection containing the items of both the
+// current and the specified collection. The current collection's less function
+// is used to order the result. This runs in O(n + m). ( Chainable )
+func (s *SortedCollection[T]) Merge(other *SortedCollection[T]) *SortedCollection[T] {
+	out := &SortedCollection[T]{
+		items: make([]T, 0, s.Length()+other.Length()),
+		less:  s.less,
+	}
+
+	i, j := 0, 0
+	for i < s.Length() && j < other.Length() {
+		if s.less(other.items[j], s.items[i]) {
+			out.items = append(out.items, other.items[j])
+			j++
+		} else {
+			out.items = append(out.items, s.items[i])
+			i++
+		}
+	}
+
+	out.items = append(out.items, s.items[i:]...)
+	out.items = append(out.items, other.items[j:]...)
+
+	return out
+}
+
+// Collection returns a new collection containing a copy of the current
+// collection's items in sorted order. ( Chainable )
+func (s *SortedCollection[T]) Collection() *Collection[T] {
+	return New(append([]T(nil), s.items...)...)
+}
+
+// BinarySearch searches for the specified item in the current collection, which
+// must already be sorted according to the less function, and returns the index
+// at which it was found or would be inserted along with a boolean value stating
+// whether or not it was found.
+func (c *Collection[T]) BinarySearch(item T, less func(a, b T) bool) (int, bool) {
+	items := c.snapshot()
+
+	index := sort.Search(len(items), func(i int) bool {
+		return !less(items[i], item)
+	})
+
+	return index, index < len(items) && !less(item, items[index])
+}
@@ -0,0 +1,106 @@
+package collection_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wilhelm-murdoch/go-collection"
+)
+
+func TestSortedCollectionInsert(t *testing.T) {
+	s := collection.NewSorted(lessInt, 5, 1, 3)
+
+	assert.Equal(t, 6, s.Insert(4, 0, 3))
+	assert.Equal(t, []int{0, 1, 3, 3, 4, 5}, s.Items(), "Items should remain sorted after insertion.")
+}
+
+func TestSortedCollectionIndexOf(t *testing.T) {
+	s := collection.NewSorted(lessInt, 1, 3, 3, 5, 7)
+
+	assert.Equal(t, 1, s.IndexOf(3), "IndexOf should return the first matching index.")
+	assert.Equal(t, -1, s.IndexOf(4))
+	assert.True(t, s.Contains(7))
+	assert.False(t, s.Contains(8))
+}
+
+func TestSortedCollectionBounds(t *testing.T) {
+	s := collection.NewSorted(lessInt, 1, 3, 3, 5, 7)
+
+	assert.Equal(t, 1, s.LowerBound(3))
+	assert.Equal(t, 3, s.UpperBound(3))
+	assert.Equal(t, 5, s.LowerBound(10))
+	assert.Equal(t, 0, s.UpperBound(0))
+
+	assert.Equal(t, []int{3, 3, 5}, s.Range(2, 5).Items())
+	assert.True(t, s.Range(8, 9).IsEmpty())
+	assert.True(t, s.Range(5, 2).IsEmpty(), "An inverted range should be empty.")
+}
+
+func TestSortedCollectionFloorCeiling(t *testing.T) {
+	s := collection.NewSorted(lessInt, 10, 20, 30)
+
+	floor, found := s.Floor(25)
+	assert.True(t, found)
+	assert.Equal(t, 20, floor)
+
+	floor, _ = s.Floor(20)
+	assert.Equal(t, 20, floor)
+
+	_, found = s.Floor(5)
+	assert.False(t, found, "There is no floor below the least item.")
+
+	ceiling, found := s.Ceiling(25)
+	assert.True(t, found)
+	assert.Equal(t, 30, ceiling)
+
+	_, found = s.Ceiling(35)
+	assert.False(t, found, "There is no ceiling above the greatest item.")
+}
+
+func TestSortedCollectionRankSelect(t *testing.T) {
+	s := collection.NewSorted(lessInt, 40, 10, 30, 20)
+
+	assert.Equal(t, 2, s.Rank(30))
+	assert.Equal(t, 4, s.Rank(99))
+
+	item, found := s.Select(1)
+	assert.True(t, found)
+	assert.Equal(t, 20, item)
+
+	_, found = s.Select(4)
+	assert.False(t, found)
+}
+
+func TestSortedCollectionRemove(t *testing.T) {
+	s := collection.NewSorted(lessInt, 1, 2, 2, 3)
+
+	assert.True(t, s.Remove(2))
+	assert.False(t, s.Remove(9))
+	assert.Equal(t, []int{1, 2, 3}, s.Items())
+
+	item, found := s.RemoveAt(0)
+	assert.True(t, found)
+	assert.Equal(t, 1, item)
+	assert.Equal(t, []int{2, 3}, s.Collection().Items())
+}
+
+func TestSortedCollectionMerge(t *testing.T) {
+	a := collection.NewSorted(lessInt, 1, 4, 7)
+	b := collection.NewSorted(lessInt, 2, 4, 8, 9)
+
+	assert.Equal(t, []int{1, 2, 4, 4, 7, 8, 9}, a.Merge(b).Items())
+	assert.Equal(t, []int{1, 4, 7}, a.Items(), "Merging should not modify the source collections.")
+}
+
+func TestCollectionBinarySearch(t *testing.T) {
+	c := collection.New("apple", "banana", "cherry", "orange")
+	less := func(a, b string) bool { return a < b }
+
+	index, found := c.BinarySearch("cherry", less)
+	assert.True(t, found)
+	assert.Equal(t, 2, index)
+
+	index, found = c.BinarySearch("carrot", less)
+	assert.False(t, found)
+	assert.Equal(t, 2, index, "A missing item should report its insertion index.")
+}
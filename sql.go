@@ -0,0 +1,149 @@
+package collection
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FromRows returns a new collection containing one item per row, each built by
+// the specified scan function. The rows are closed once they have been read.
+func FromRows[T any](rows *sql.Rows, scan func(*sql.Rows) (T, error)) (*Collection[T], error) {
+	defer rows.Close()
+
+	out := New[T]()
+	for rows.Next() {
+		item, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		out.Push(item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return out, rows.Close()
+}
+
+// FromRowsStruct returns a new collection containing one struct of type T, or
+// pointer to one, per row. Columns are matched to exported fields by their
+// `db` struct tag, falling back to a case-insensitive match on the field name,
+// and fields tagged `db:"-"` are skipped. Columns without a matching field
+// cause an error. Use pointer fields for nullable columns. The rows are closed
+// once they have been read.
+func FromRowsStruct[T any](rows *sql.Rows) (*Collection[T], error) {
+	paths, err := sqlColumnPaths(reflect.TypeOf((*T)(nil)).Elem(), rows)
+	if err != nil {
+		rows.Close()
+		return nil, err
+	}
+
+	return FromRows(rows, func(rows *sql.Rows) (T, error) {
+		return scanStruct[T](rows, paths)
+	})
+}
+
+// scanStruct scans the current row into a new T, writing each column to the
+// field found at the matching path.
+func scanStruct[T any](rows *sql.Rows, paths [][]int) (out T, err error) {
+	v := allocValue(reflect.ValueOf(&out).Elem())
+
+	dest := make([]any, len(paths))
+	for i, path := range paths {
+		field := v
+		for _, index := range path {
+			field = allocValue(field).Field(index)
+		}
+		dest[i] = field.Addr().Interface()
+	}
+
+	err = rows.Scan(dest...)
+	return out, err
+}
+
+func sqlColumnPaths(t reflect.Type, rows *sql.Rows) ([][]int, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("collection: cannot scan rows into %s", t)
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string][]int)
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("db"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			if _, found := fields[strings.ToLower(field.Name)]; !found {
+				fields[strings.ToLower(field.Name)] = field.Index
+			}
+		default:
+			fields[name] = field.Index
+		}
+	}
+
+	paths := make([][]int, len(columns))
+	for i, column := range columns {
+		path, found := fields[column]
+		if !found {
+			path, found = fields[strings.ToLower(column)]
+		}
+
+		if !found {
+			return nil, fmt.Errorf("collection: no field of %s matches column %q", t, column)
+		}
+
+		paths[i] = path
+	}
+
+	return paths, nil
+}
+
+// Scan implements the sql.Scanner interface so a JSON array column can be
+// scanned into the current collection, replacing its items as UnmarshalJSON
+// does. NULL results in an empty collection.
+func (c *Collection[T]) Scan(src any) error {
+	switch data := src.(type) {
+	case nil:
+		c.Empty()
+		return nil
+	case []byte:
+		return c.UnmarshalJSON(data)
+	case string:
+		return c.UnmarshalJSON([]byte(data))
+	}
+
+	return fmt.Errorf("collection: cannot scan %T into a collection", src)
+}
+
+// Value implements the driver.Valuer interface so the current collection can
+// be stored in a column as a JSON array.
+func (c *Collection[T]) Value() (driver.Value, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	items := c.snapshot()
+	if items == nil {
+		return []byte("[]"), nil
+	}
+
+	return json.Marshal(items)
+}
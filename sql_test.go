@@ -0,0 +1,194 @@
+package collection_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wilhelm-murdoch/go-collection"
+)
+
+// fakeTable is an in-memory result set served by fakeDriver. Exec appends its
+// arguments as a new row.
+type fakeTable struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+type fakeDriver struct {
+	mu     sync.Mutex
+	tables map[string]*fakeTable
+}
+
+type fakeConn struct{ table *fakeTable }
+type fakeStmt struct{ table *fakeTable }
+type fakeRows struct {
+	table *fakeTable
+	next  int
+}
+
+var (
+	fakeSQL         = &fakeDriver{tables: map[string]*fakeTable{}}
+	fakeSQLRegister sync.Once
+)
+
+func openFakeDB(t *testing.T, table *fakeTable) *sql.DB {
+	fakeSQLRegister.Do(func() { sql.Register("collectiontest", fakeSQL) })
+
+	fakeSQL.mu.Lock()
+	fakeSQL.tables[t.Name()] = table
+	fakeSQL.mu.Unlock()
+
+	db, err := sql.Open("collectiontest", t.Name())
+	assert.Nil(t, err)
+
+	return db
+}
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return &fakeConn{d.tables[name]}, nil
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) { return &fakeStmt{c.table}, nil }
+func (c *fakeConn) Close() error                              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)                 { return nil, errors.New("not supported") }
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.table.rows = append(s.table.rows, args)
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return &fakeRows{table: s.table}, nil
+}
+
+func (r *fakeRows) Columns() []string { return r.table.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.table.rows) {
+		return io.EOF
+	}
+
+	copy(dest, r.table.rows[r.next])
+	r.next++
+
+	return nil
+}
+
+type Customer struct {
+	ID       int64   `db:"id"`
+	Name     string  `db:"full_name"`
+	Email    *string `db:"email"`
+	Country  string
+	Internal string `db:"-"`
+}
+
+func returnCustomerTable() *fakeTable {
+	return &fakeTable{
+		columns: []string{"id", "full_name", "email", "COUNTRY"},
+		rows: [][]driver.Value{
+			{int64(1), "luke", "luke@example.com", "AU"},
+			{int64(2), "rob", nil, "NZ"},
+		},
+	}
+}
+
+func TestFromRows(t *testing.T) {
+	db := openFakeDB(t, returnCustomerTable())
+	defer db.Close()
+
+	rows, err := db.Query("SELECT id, full_name, email, country FROM customers")
+	assert.Nil(t, err)
+
+	names, err := collection.FromRows(rows, func(rows *sql.Rows) (string, error) {
+		var (
+			id                   int64
+			name, email, country sql.NullString
+		)
+		err := rows.Scan(&id, &name, &email, &country)
+		return name.String, err
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"luke", "rob"}, names.Items())
+}
+
+func TestFromRowsStruct(t *testing.T) {
+	db := openFakeDB(t, returnCustomerTable())
+	defer db.Close()
+
+	rows, err := db.Query("SELECT * FROM customers")
+	assert.Nil(t, err)
+
+	customers, err := collection.FromRowsStruct[*Customer](rows)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, customers.Length())
+
+	luke, _ := customers.At(0)
+	assert.Equal(t, int64(1), luke.ID)
+	assert.Equal(t, "luke", luke.Name)
+	assert.Equal(t, "luke@example.com", *luke.Email)
+	assert.Equal(t, "AU", luke.Country, "Columns should match field names case-insensitively.")
+
+	rob, _ := customers.At(1)
+	assert.Nil(t, rob.Email, "NULL columns should leave pointer fields nil.")
+}
+
+func TestFromRowsStructUnknownColumn(t *testing.T) {
+	table := returnCustomerTable()
+	table.columns[3] = "region"
+
+	db := openFakeDB(t, table)
+	defer db.Close()
+
+	rows, err := db.Query("SELECT * FROM customers")
+	assert.Nil(t, err)
+
+	_, err = collection.FromRowsStruct[Customer](rows)
+	assert.NotNil(t, err, "Columns without a matching field should be reported.")
+}
+
+func TestCollectionValueScan(t *testing.T) {
+	db := openFakeDB(t, &fakeTable{columns: []string{"tags"}})
+	defer db.Close()
+
+	_, err := db.Exec("INSERT INTO posts (tags) VALUES (?)", collection.New("go", "generics"))
+	assert.Nil(t, err)
+
+	_, err = db.Exec("INSERT INTO posts (tags) VALUES (?)", collection.New[string]())
+	assert.Nil(t, err)
+
+	_, err = db.Exec("INSERT INTO posts (tags) VALUES (?)", nil)
+	assert.Nil(t, err)
+
+	rows, err := db.Query("SELECT tags FROM posts")
+	assert.Nil(t, err)
+
+	tags, err := collection.FromRows(rows, func(rows *sql.Rows) (*collection.Collection[string], error) {
+		c := collection.New[string]()
+		return c, rows.Scan(c)
+	})
+	assert.Nil(t, err)
+
+	first, _ := tags.At(0)
+	assert.Equal(t, []string{"go", "generics"}, first.Items())
+
+	second, _ := tags.At(1)
+	assert.True(t, second.IsEmpty())
+
+	third, _ := tags.At(2)
+	assert.True(t, third.IsEmpty(), "NULL should scan into an empty collection.")
+
+	assert.NotNil(t, collection.New[string]().Scan(42))
+}
@@ -0,0 +1,361 @@
+package collection
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrIndexOutOfRange is returned by TryReplaceAt for indices which do not hold
+// an item.
+var ErrIndexOutOfRange = errors.New("collection: index out of range")
+
+// validationRule is a validator attached to a collection. Rules either check
+// items on their own or, when key is set, require their keys to be unique.
+type validationRule[T any] struct {
+	name  string
+	check func(T) error
+	key   func(T) any
+}
+
+// ValidationError describes an item which broke one of a collection's
+// validation rules. Index is the position the item holds, or would have held,
+// within the collection.
+type ValidationError[T any] struct {
+	Index int
+	Item  T
+	Rule  string
+	Err   error
+}
+
+func (e *ValidationError[T]) Error() string {
+	if e.Rule == "" {
+		return fmt.Sprintf("collection: item %d is invalid: %v", e.Index, e.Err)
+	}
+
+	return fmt.Sprintf("collection: item %d breaks rule %q: %v", e.Index, e.Rule, e.Err)
+}
+
+func (e *ValidationError[T]) Unwrap() error {
+	return e.Err
+}
+
+// ValidationErrors holds every violation found while validating items.
+type ValidationErrors[T any] []*ValidationError[T]
+
+func (e ValidationErrors[T]) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap returns the individual violations, so that `errors.Is` and
+// `errors.As` can match any of them.
+func (e ValidationErrors[T]) Unwrap() []error {
+	out := make([]error, len(e))
+	for i, err := range e {
+		out[i] = err
+	}
+
+	return out
+}
+
+// AddValidator attaches a named validation rule to the current collection.
+// Items added by Push, PushDistinct, Unshift, Concat or InsertAt must satisfy
+// the rule, by making the specified function return a nil error; those which
+// do not are dropped, or reported by the `Try` variants of those methods.
+// Methods which replace every item, such as UnmarshalJSON, Scan, GobDecode,
+// Apply and ApplyJSONPatch, check all of the new items and return
+// ValidationErrors, leaving the collection untouched, if any is invalid.
+// Items already in the collection are not checked; use Validate for that.
+// ( Chainable )
+func (c *Collection[T]) AddValidator(rule string, f func(T) error) *Collection[T] {
+	defer c.lock()()
+
+	c.validators = append(c.validators, validationRule[T]{name: rule, check: f})
+	c.keys = nil
+	return c
+}
+
+// validate checks the specified items, which are about to be inserted at the
+// specified index, returning those which satisfy every rule along with the
+// violations of the others.
+func (c *Collection[T]) validate(index int, items []T) (accepted []T, errs ValidationErrors[T]) {
+	var (
+		keys  = c.uniqueKeys()
+		batch = make(map[int]map[any]bool)
+	)
+
+	taken := func(r int, key any) bool {
+		return keys[r][key] > 0 || batch[r][key]
+	}
+
+	for i, item := range items {
+		violations := c.violations(index+i, item, taken)
+		if len(violations) > 0 {
+			errs = append(errs, violations...)
+			continue
+		}
+
+		accepted = append(accepted, item)
+		for r, rule := range c.validators {
+			if rule.key == nil {
+				continue
+			}
+
+			if batch[r] == nil {
+				batch[r] = make(map[any]bool)
+			}
+			batch[r][rule.key(item)] = true
+		}
+	}
+
+	return accepted, errs
+}
+
+// uniqueKeys returns how many items of the collection hold each key, for each
+// of its unique rules. The counts are built on first use and then kept up to
+// date as items are added and removed. The write lock must be held.
+func (c *Collection[T]) uniqueKeys() map[int]map[any]int {
+	if c.keys != nil {
+		return c.keys
+	}
+
+	c.keys = make(map[int]map[any]int)
+	for r, rule := range c.validators {
+		if rule.key != nil {
+			c.keys[r] = make(map[any]int, len(c.items))
+		}
+	}
+	c.countKeys(c.items, 1)
+
+	return c.keys
+}
+
+// countKeys adds the specified delta to the counts of the keys of the
+// specified items, if the counts have been built.
+func (c *Collection[T]) countKeys(items []T, delta int) {
+	if c.keys == nil {
+		return
+	}
+
+	for r, counts := range c.keys {
+		key := c.validators[r].key
+		for _, item := range items {
+			k := key(item)
+			if counts[k] += delta; counts[k] <= 0 {
+				delete(counts, k)
+			}
+		}
+	}
+}
+
+// violations returns every rule broken by the specified item, given a function
+// reporting whether a key is already taken for a unique rule.
+func (c *Collection[T]) violations(index int, item T, taken func(r int, key any) bool) (out ValidationErrors[T]) {
+	for r, rule := range c.validators {
+		var err error
+		if rule.key != nil {
+			if key := rule.key(item); taken(r, key) {
+				err = fmt.Errorf("duplicate key %v", key)
+			}
+		} else {
+			err = rule.check(item)
+		}
+
+		if err != nil {
+			out = append(out, &ValidationError[T]{Index: index, Item: item, Rule: rule.name, Err: err})
+		}
+	}
+
+	return out
+}
+
+// Validate checks every item of the current collection against its validation
+// rules, returning ValidationErrors describing all violations, or nil. For
+// unique rules, every occurrence of a key after the first is reported.
+func (c *Collection[T]) Validate() error {
+	defer c.rlock()()
+
+	seen := make(map[int]map[any]bool)
+	for r, rule := range c.validators {
+		if rule.key != nil {
+			seen[r] = make(map[any]bool)
+		}
+	}
+
+	taken := func(r int, key any) bool { return seen[r][key] }
+
+	var errs ValidationErrors[T]
+	for i, item := range c.items {
+		errs = append(errs, c.violations(i, item, taken)...)
+
+		for r, rule := range c.validators {
+			if rule.key != nil {
+				seen[r][rule.key(item)] = true
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// tryInsert inserts the specified items at the specified index if every one of
+// them is valid and fits within the length limit, and otherwise returns the
+// violations without changing the collection. The write lock must be held.
+func (c *Collection[T]) tryInsert(index int, items []T) error {
+	_, errs := c.validate(index, items)
+
+	if room := c.maxLength - len(c.items); c.maxLength > 0 && c.overflow == OverflowReject && len(items) > room {
+		if room < 0 {
+			room = 0
+		}
+
+		for i := room; i < len(items); i++ {
+			errs = append(errs, &ValidationError[T]{
+				Index: index + i,
+				Item:  items[i],
+				Rule:  "maxLength",
+				Err:   fmt.Errorf("collection is limited to %d items", c.maxLength),
+			})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	c.insert(index, items...)
+	return nil
+}
+
+// replace replaces every item of the collection with the specified ones, as if
+// it were emptied and they were then added by TryConcat, so the length limit
+// applies and the collection is left untouched if any item is invalid. The
+// write lock must be held.
+func (c *Collection[T]) replace(items []T) error {
+	current, keys := c.items, c.keys
+	c.items, c.keys = nil, nil
+
+	if err := c.tryInsert(0, items); err != nil {
+		c.items, c.keys = current, keys
+		return err
+	}
+
+	return nil
+}
+
+// TryPush is like Push, but if any of the specified items breaks a validation
+// rule, none are added and the violations are returned as ValidationErrors.
+func (c *Collection[T]) TryPush(items ...T) (int, error) {
+	defer c.lock()()
+
+	err := c.tryInsert(len(c.items), items)
+	return len(c.items), err
+}
+
+// TryUnshift is like Unshift, but returns ValidationErrors, without adding
+// the item, if it breaks a validation rule.
+func (c *Collection[T]) TryUnshift(item T) (int, error) {
+	defer c.lock()()
+
+	err := c.tryInsert(0, []T{item})
+	return len(c.items), err
+}
+
+// TryInsertAt is like InsertAt, but returns ValidationErrors, without adding
+// the item, if it breaks a validation rule.
+func (c *Collection[T]) TryInsertAt(item T, index int) error {
+	defer c.lock()()
+
+	if index < 0 {
+		index = 0
+	}
+
+	if index > len(c.items) {
+		index = len(c.items)
+	}
+
+	return c.tryInsert(index, []T{item})
+}
+
+// TryConcat is like Concat, but if any of the specified items breaks a
+// validation rule, none are added and the violations are returned as
+// ValidationErrors.
+func (c *Collection[T]) TryConcat(items []T) error {
+	defer c.lock()()
+
+	return c.tryInsert(len(c.items), items)
+}
+
+// TryPushDistinct is like PushDistinct, but if any of the new distinct items
+// breaks a validation rule, none are added and the violations are returned as
+// ValidationErrors.
+func (c *Collection[T]) TryPushDistinct(items ...T) (int, error) {
+	defer c.lock()()
+
+	var (
+		eq       = c.equality()
+		distinct []T
+	)
+
+next:
+	for _, item := range items {
+		if c.contains(item) {
+			continue
+		}
+
+		for _, other := range distinct {
+			if eq(item, other) {
+				continue next
+			}
+		}
+		distinct = append(distinct, item)
+	}
+
+	err := c.tryInsert(len(c.items), distinct)
+	return len(c.items), err
+}
+
+// TryReplaceAt replaces the item at the specified index with the specified
+// item, returning ErrIndexOutOfRange if there is no such item, or
+// ValidationErrors, without replacing it, if the new item breaks a validation
+// rule. Unique rules ignore the key of the item being replaced.
+func (c *Collection[T]) TryReplaceAt(item T, index int) error {
+	defer c.lock()()
+
+	if index < 0 || index >= len(c.items) {
+		return ErrIndexOutOfRange
+	}
+
+	var (
+		keys     = c.uniqueKeys()
+		replaced = c.items[index]
+	)
+
+	// The item being replaced gives up its keys.
+	taken := func(r int, key any) bool {
+		count := keys[r][key]
+		if c.validators[r].key(replaced) == key {
+			count--
+		}
+		return count > 0
+	}
+
+	if errs := c.violations(index, item, taken); len(errs) > 0 {
+		return errs
+	}
+
+	c.countKeys([]T{replaced}, -1)
+	c.items[index] = item
+	c.countKeys([]T{item}, 1)
+
+	return nil
+}
@@ -0,0 +1,195 @@
+package collection_test
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wilhelm-murdoch/go-collection"
+	"gopkg.in/yaml.v3"
+)
+
+var errEmptyHost = errors.New("host must not be empty")
+
+func returnValidatedServers() *collection.Collection[Server] {
+	return collection.NewWith(
+		collection.WithRule("host", func(s Server) error {
+			if s.Host == "" {
+				return errEmptyHost
+			}
+			return nil
+		}),
+		collection.WithUnique(func(s Server) int { return s.Port }),
+		collection.WithItems(Server{"a", 1}, Server{"b", 2}),
+	)
+}
+
+func TestCollectionTryPush(t *testing.T) {
+	c := returnValidatedServers()
+
+	length, err := c.TryPush(Server{"c", 3}, Server{"", 4}, Server{"d", 1}, Server{"e", 3})
+	assert.Equal(t, 2, length, "No items should be added when any of them is invalid.")
+
+	var errs collection.ValidationErrors[Server]
+	assert.True(t, errors.As(err, &errs))
+	assert.Equal(t, collection.ValidationErrors[Server]{
+		{Index: 3, Item: Server{"", 4}, Rule: "host", Err: errEmptyHost},
+		{Index: 4, Item: Server{"d", 1}, Rule: "unique", Err: errs[1].Err},
+		{Index: 5, Item: Server{"e", 3}, Rule: "unique", Err: errs[2].Err},
+	}, errs)
+	assert.True(t, errors.Is(err, errEmptyHost))
+
+	var violation *collection.ValidationError[Server]
+	assert.True(t, errors.As(err, &violation))
+	assert.Equal(t, `collection: item 3 breaks rule "host": host must not be empty`, violation.Error())
+
+	length, err = c.TryPush(Server{"c", 3})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, length)
+
+	assert.Equal(t, 3, c.Push(Server{"", 5}, Server{"f", 1}), "Push should drop invalid items.")
+}
+
+func TestCollectionTryVariants(t *testing.T) {
+	c := returnValidatedServers()
+
+	_, err := c.TryUnshift(Server{"", 0})
+	assert.NotNil(t, err)
+
+	length, err := c.TryUnshift(Server{"z", 0})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, length)
+
+	assert.NotNil(t, c.TryInsertAt(Server{"y", 2}, 1))
+	assert.Nil(t, c.TryInsertAt(Server{"y", 9}, 1))
+
+	assert.NotNil(t, c.TryConcat([]Server{{"x", 10}, {"", 11}}))
+	assert.Nil(t, c.TryConcat([]Server{{"x", 10}, {"w", 11}}))
+
+	length, err = c.TryPushDistinct(Server{"a", 1}, Server{"v", 12}, Server{"v", 12})
+	assert.Nil(t, err, "Items already present should be skipped rather than reported.")
+	assert.Equal(t, 7, length)
+
+	_, err = c.TryPushDistinct(Server{"u", 11})
+	assert.NotNil(t, err)
+
+	assert.Equal(t, []Server{{"z", 0}, {"y", 9}, {"a", 1}, {"b", 2}, {"x", 10}, {"w", 11}, {"v", 12}}, c.Items())
+}
+
+func TestCollectionTryReplaceAt(t *testing.T) {
+	c := returnValidatedServers()
+
+	assert.Nil(t, c.TryReplaceAt(Server{"c", 1}, 0), "An item should not clash with the one it replaces.")
+	assert.NotNil(t, c.TryReplaceAt(Server{"c", 2}, 0))
+	assert.True(t, errors.Is(c.TryReplaceAt(Server{"", 3}, 1), errEmptyHost))
+	assert.True(t, errors.Is(c.TryReplaceAt(Server{"d", 4}, 2), collection.ErrIndexOutOfRange))
+	assert.True(t, errors.Is(c.TryReplaceAt(Server{"d", 4}, -1), collection.ErrIndexOutOfRange))
+
+	assert.Equal(t, []Server{{"c", 1}, {"b", 2}}, c.Items())
+
+	limited := collection.NewWith(collection.WithMaxLength[int](2, collection.OverflowReject), collection.WithItems(1))
+
+	_, err := limited.TryPush(2, 3)
+	var errs collection.ValidationErrors[int]
+	assert.True(t, errors.As(err, &errs))
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "maxLength", errs[0].Rule)
+	assert.Equal(t, 2, errs[0].Index)
+	assert.Equal(t, []int{1}, limited.Items(), "No items should be added when some do not fit.")
+}
+
+func TestCollectionUniqueIndex(t *testing.T) {
+	c := collection.NewWith(
+		collection.WithUnique(func(n int) int { return n }),
+		collection.WithMaxLength[int](4, collection.OverflowEvict),
+		collection.WithItems(1, 2, 3),
+	)
+
+	c.Pop()
+	assert.Equal(t, 3, c.Push(3), "Popped keys should be free again.")
+
+	c.Shift()
+	assert.Equal(t, 3, c.Push(1), "Shifted keys should be free again.")
+
+	c.RemoveAt(0)
+	assert.Nil(t, c.TryReplaceAt(2, 0), "Removed keys should be free again.")
+	assert.NotNil(t, c.TryReplaceAt(1, 0))
+
+	c.Push(4, 5, 6)
+	assert.Equal(t, []int{1, 4, 5, 6}, c.Items())
+	assert.Equal(t, 4, c.Push(2), "Evicted keys should be free again.")
+	assert.Equal(t, 4, c.Push(5), "Indexed keys should still be taken.")
+
+	assert.Nil(t, c.UnmarshalJSON([]byte(`[7, 8]`)))
+	assert.Equal(t, 3, c.Push(4, 7))
+	assert.Equal(t, []int{7, 8, 4}, c.Items())
+
+	c.Empty()
+	assert.Equal(t, 2, c.Push(7, 7, 8))
+}
+
+func TestCollectionReplaceValidation(t *testing.T) {
+	c := returnValidatedServers()
+
+	replacements := map[string]func() error{
+		"json": func() error { return c.UnmarshalJSON([]byte(`[{"host": "c", "port": 3}, {"host": "", "port": 4}]`)) },
+		"scan": func() error { return c.Scan(`[{"host": "c", "port": 3}, {"host": "d", "port": 3}]`) },
+		"xml": func() error {
+			return xml.Unmarshal([]byte(`<items><item host="c"></item><item host="d"></item></items>`), c)
+		},
+		"yaml": func() error { return yaml.Unmarshal([]byte(`[{host: ""}]`), c) },
+		"gob": func() error {
+			data, err := collection.New(Server{"", 3}).GobEncode()
+			assert.Nil(t, err)
+			return c.GobDecode(data)
+		},
+		"patch": func() error {
+			return c.Apply(collection.Diff(c, collection.New(Server{"a", 1}, Server{"", 2}), func(a, b Server) bool { return a == b }))
+		},
+		"json patch": func() error {
+			return c.ApplyJSONPatch(collection.JSONPatch{{Op: "replace", Path: "/1", Value: json.RawMessage(`{"host": "b", "port": 1}`)}})
+		},
+	}
+
+	for name, replace := range replacements {
+		var errs collection.ValidationErrors[Server]
+		assert.True(t, errors.As(replace(), &errs), name)
+		assert.Equal(t, []Server{{"a", 1}, {"b", 2}}, c.Items(), "%s should leave the collection untouched.", name)
+	}
+
+	assert.Nil(t, c.UnmarshalJSON([]byte(`[{"host": "c", "port": 1}, {"host": "d", "port": 2}]`)))
+	assert.Equal(t, []Server{{"c", 1}, {"d", 2}}, c.Items(), "Replaced items should not clash with the old ones.")
+
+	limited := collection.NewWith(collection.WithMaxLength[int](2, collection.OverflowEvict))
+	assert.Nil(t, limited.UnmarshalJSON([]byte(`[1, 2, 3]`)))
+	assert.Equal(t, []int{2, 3}, limited.Items())
+}
+
+func TestCollectionValidate(t *testing.T) {
+	c := collection.New(Server{"a", 1}, Server{"", 2}, Server{"c", 1})
+	assert.Nil(t, c.Validate(), "Collections without rules are always valid.")
+
+	c.AddValidator("host", func(s Server) error {
+		if s.Host == "" {
+			return errEmptyHost
+		}
+		return nil
+	})
+
+	err := c.Validate()
+	assert.NotNil(t, err)
+
+	var errs collection.ValidationErrors[Server]
+	assert.True(t, errors.As(err, &errs))
+	assert.Len(t, errs, 1)
+	assert.Equal(t, 1, errs[0].Index)
+
+	unique := collection.NewWith(collection.WithUnique(func(s Server) int { return s.Port }))
+	unique.Concat([]Server{{"a", 1}, {"b", 1}})
+	assert.Equal(t, 1, unique.Length())
+
+	_, err = collection.New[int]().TryPush(1)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,111 @@
+package collection
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// DefaultXMLItemName is the element name used for each item when marshalling a
+// collection to XML without an item name of its own.
+const DefaultXMLItemName = "item"
+
+// DefaultXMLWrapperName is the element name used to wrap a collection's items
+// when marshalling it to XML directly, rather than as a struct field, without a
+// wrapper name of its own.
+const DefaultXMLWrapperName = "items"
+
+// XMLCollection marshals a collection to XML using element names of its own.
+// An empty Wrapper keeps the name chosen by the encoder, such as a struct
+// field's `xml` tag, and an empty Item uses DefaultXMLItemName. Unmarshalling
+// into an XMLCollection without a collection creates one.
+type XMLCollection[T any] struct {
+	Collection *Collection[T]
+	Wrapper    string
+	Item       string
+}
+
+// WithXMLNames returns the current collection wrapped in an XMLCollection
+// using the specified element names.
+func (c *Collection[T]) WithXMLNames(wrapper, item string) XMLCollection[T] {
+	return XMLCollection[T]{Collection: c, Wrapper: wrapper, Item: item}
+}
+
+// MarshalXML implements the xml.Marshaler interface.
+func (x XMLCollection[T]) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if x.Collection == nil {
+		x.Collection = New[T]()
+	}
+
+	return x.Collection.marshalXML(e, start, x.Wrapper, x.Item)
+}
+
+// UnmarshalXML implements the xml.Unmarshaler interface.
+func (x *XMLCollection[T]) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	if x.Collection == nil {
+		x.Collection = New[T]()
+	}
+
+	return x.Collection.UnmarshalXML(d, start)
+}
+
+// MarshalXML implements the xml.Marshaler interface so the current collection's
+// items can be marshalled into an XML element containing one child element per
+// item. Use WithXMLNames to choose the element names.
+func (c *Collection[T]) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return c.marshalXML(e, start, "", "")
+}
+
+func (c *Collection[T]) marshalXML(e *xml.Encoder, start xml.StartElement, wrapper, itemName string) error {
+	switch {
+	case wrapper != "":
+		start.Name = xml.Name{Local: wrapper}
+	case strings.ContainsAny(start.Name.Local, "[]"):
+		// The encoder falls back to the type name, which for a generic type is
+		// not a valid element name.
+		start.Name = xml.Name{Local: DefaultXMLWrapperName}
+	}
+
+	item := xml.StartElement{Name: xml.Name{Local: itemName}}
+	if item.Name.Local == "" {
+		item.Name.Local = DefaultXMLItemName
+	}
+
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	for _, inner := range c.snapshot() {
+		if err := e.EncodeElement(inner, item); err != nil {
+			return err
+		}
+	}
+
+	return e.EncodeToken(start.End())
+}
+
+// UnmarshalXML implements the xml.Unmarshaler interface, replacing the current
+// collection's items with one item decoded from each child element, whatever
+// its name. Invalid items are reported as ValidationErrors.
+func (c *Collection[T]) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var items []T
+
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			var item T
+			if err := d.DecodeElement(&item, &t); err != nil {
+				return err
+			}
+			items = append(items, item)
+		case xml.EndElement:
+			defer c.lock()()
+
+			return c.replace(items)
+		}
+	}
+}
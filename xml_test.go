@@ -0,0 +1,62 @@
+package collection_test
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wilhelm-murdoch/go-collection"
+)
+
+type Server struct {
+	Host string `xml:"host,attr" yaml:"host" json:"host"`
+	Port int    `xml:"port" yaml:"port" json:"port"`
+}
+
+type Config struct {
+	XMLName xml.Name                       `xml:"config" yaml:"-" json:"-"`
+	Name    string                         `xml:"name" yaml:"name" json:"name"`
+	Servers *collection.Collection[Server] `xml:"servers" yaml:"servers" json:"servers"`
+	Tags    *collection.Collection[string] `xml:"tags" yaml:"tags" json:"tags"`
+}
+
+func TestCollectionMarshalXML(t *testing.T) {
+	c := collection.New("apple", "orange")
+
+	data, err := xml.Marshal(c)
+	assert.Nil(t, err)
+	assert.Equal(t, "<items><item>apple</item><item>orange</item></items>", string(data))
+
+	data, err = xml.Marshal(c.WithXMLNames("fruits", "fruit"))
+	assert.Nil(t, err)
+	assert.Equal(t, "<fruits><fruit>apple</fruit><fruit>orange</fruit></fruits>", string(data))
+
+	data, err = xml.Marshal(c)
+	assert.Nil(t, err)
+	assert.Equal(t, "<items><item>apple</item><item>orange</item></items>", string(data), "Element names should not stick to the collection.")
+}
+
+type XMLConfig struct {
+	XMLName xml.Name                         `xml:"config"`
+	Name    string                           `xml:"name"`
+	Servers collection.XMLCollection[Server] `xml:"servers"`
+	Tags    *collection.Collection[string]   `xml:"tags"`
+}
+
+func TestCollectionXMLRoundTrip(t *testing.T) {
+	in := XMLConfig{
+		Name:    "edge",
+		Servers: collection.New(Server{"a.example.com", 80}, Server{"b.example.com", 443}).WithXMLNames("", "server"),
+		Tags:    collection.New("prod", "eu"),
+	}
+
+	data, err := xml.Marshal(in)
+	assert.Nil(t, err)
+	assert.Equal(t, `<config><name>edge</name><servers><server host="a.example.com"><port>80</port></server>`+
+		`<server host="b.example.com"><port>443</port></server></servers><tags><item>prod</item><item>eu</item></tags></config>`, string(data))
+
+	var out XMLConfig
+	assert.Nil(t, xml.Unmarshal(data, &out))
+	assert.Equal(t, in.Servers.Collection.Items(), out.Servers.Collection.Items())
+	assert.Equal(t, in.Tags.Items(), out.Tags.Items())
+}
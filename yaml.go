@@ -0,0 +1,28 @@
+package collection
+
+import "gopkg.in/yaml.v3"
+
+// MarshalYAML implements the yaml.Marshaler interface so the current
+// collection's items can be marshalled into a YAML sequence.
+func (c *Collection[T]) MarshalYAML() (any, error) {
+	items := c.snapshot()
+	if items == nil {
+		return []T{}, nil
+	}
+
+	return items, nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface so a YAML sequence
+// can be unmarshalled into the current collection, replacing its items, which
+// must satisfy the collection's validation rules.
+func (c *Collection[T]) UnmarshalYAML(value *yaml.Node) error {
+	var items []T
+	if err := value.Decode(&items); err != nil {
+		return err
+	}
+
+	defer c.lock()()
+
+	return c.replace(items)
+}
@@ -0,0 +1,35 @@
+package collection_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wilhelm-murdoch/go-collection"
+	"gopkg.in/yaml.v3"
+)
+
+func TestCollectionYAMLRoundTrip(t *testing.T) {
+	in := Config{
+		Name:    "edge",
+		Servers: collection.New(Server{"a.example.com", 80}, Server{"b.example.com", 443}),
+		Tags:    collection.New[string](),
+	}
+
+	data, err := yaml.Marshal(in)
+	assert.Nil(t, err)
+	assert.Equal(t, "name: edge\nservers:\n  - host: a.example.com\n    port: 80\n  - host: b.example.com\n    port: 443\ntags: []\n", string(data))
+
+	var out Config
+	assert.Nil(t, yaml.Unmarshal(data, &out))
+	assert.Equal(t, in.Servers.Items(), out.Servers.Items())
+	assert.True(t, out.Tags.IsEmpty())
+}
+
+func TestCollectionUnmarshalYAML(t *testing.T) {
+	var c collection.Collection[int]
+
+	assert.Nil(t, yaml.Unmarshal([]byte("[1, 2, 3]"), &c))
+	assert.Equal(t, []int{1, 2, 3}, c.Items())
+
+	assert.NotNil(t, yaml.Unmarshal([]byte("a: b"), &c), "Only YAML sequences can be unmarshalled.")
+}